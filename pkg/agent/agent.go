@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"inspection-server/pkg/common"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	applyappsv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
@@ -137,6 +138,21 @@ func ApplyClusterRoleBinding(clientset *kubernetes.Clientset) error {
 	return nil
 }
 
+func GetClusterRoleBinding() (*rbacv1.ClusterRoleBinding, error) {
+	yamlFile, err := os.ReadFile(common.AgentYamlPath + "clusterrolebinding.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterRoleBinding *rbacv1.ClusterRoleBinding
+	err = yaml.Unmarshal(yamlFile, &clusterRoleBinding)
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterRoleBinding, nil
+}
+
 func ApplyConfigMap(clientset *kubernetes.Clientset) error {
 	yamlFile, err := os.ReadFile(common.AgentYamlPath + "configmap.yaml")
 	if err != nil {
@@ -22,11 +22,18 @@ type ClusterNode struct {
 }
 
 type ClusterResource struct {
-	Workloads   *Workload     `json:"workloads"`
-	Namespace   []*Namespace  `json:"namespace"`
-	Service     []*Service    `json:"service"`
-	Ingress     []*Ingress    `json:"ingress"`
-	Inspections []*Inspection `json:"inspections"`
+	Workloads               *Workload                  `json:"workloads"`
+	HorizontalPodAutoscaler []*HorizontalPodAutoscaler `json:"horizontal_pod_autoscaler"`
+	PodDisruptionBudget     []*PodDisruptionBudget     `json:"pod_disruption_budget"`
+	Namespace               []*Namespace               `json:"namespace"`
+	PersistentVolumeClaim   []*PersistentVolumeClaim   `json:"persistent_volume_claim"`
+	PersistentVolume        []*PersistentVolume        `json:"persistent_volume"`
+	Service                 []*Service                 `json:"service"`
+	Ingress                 []*Ingress                 `json:"ingress"`
+	Security                []*Security                `json:"security"`
+	Image                   []*Image                   `json:"image"`
+	HelmRelease             []*HelmRelease             `json:"helm_release"`
+	Inspections             []*Inspection              `json:"inspections"`
 }
 
 type Inspection struct {
@@ -58,11 +65,33 @@ type WorkloadData struct {
 	Status    *Status `json:"status"`
 }
 
+type HorizontalPodAutoscaler struct {
+	Name            string      `json:"name"`
+	Namespace       string      `json:"namespace"`
+	Target          string      `json:"target"`
+	MinReplicas     int32       `json:"min_replicas"`
+	MaxReplicas     int32       `json:"max_replicas"`
+	CurrentReplicas int32       `json:"current_replicas"`
+	Condition       []Condition `json:"condition"`
+}
+
+type PodDisruptionBudget struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	MinAvailable       string `json:"min_available"`
+	MaxUnavailable     string `json:"max_unavailable"`
+	ExpectedPods       int32  `json:"expected_pods"`
+	DisruptionsAllowed int32  `json:"disruptions_allowed"`
+}
+
 type Node struct {
-	Name     string    `json:"name"`
-	HostIP   string    `json:"host_ip"`
-	Resource *Resource `json:"resource"`
-	Commands *Command  `json:"commands"`
+	Name          string      `json:"name"`
+	HostIP        string      `json:"host_ip"`
+	Unschedulable bool        `json:"unschedulable"`
+	Condition     []Condition `json:"condition"`
+	Resource      *Resource   `json:"resource"`
+	TopPods       []*PodUsage `json:"top_pods"`
+	Commands      *Command    `json:"commands"`
 }
 
 type Resource struct {
@@ -74,38 +103,100 @@ type Resource struct {
 	AllocatableCPU    int64 `json:"allocatable_cpu"`
 	AllocatableMemory int64 `json:"allocatable_memory"`
 	AllocatablePods   int64 `json:"allocatable_pods"`
+	// 来自 metrics.k8s.io 的实际用量，metrics-server 不可用时为 0
+	UsageMilliCPU int64 `json:"usage_milli_cpu"`
+	UsageMemory   int64 `json:"usage_memory"`
+}
+
+type PodUsage struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	MilliCPU  int64  `json:"milli_cpu"`
+	Memory    int64  `json:"memory"`
 }
 
 type Namespace struct {
-	Name               string `json:"name"`
-	EmptyResourceQuota bool   `json:"empty_resource_quota"`
-	EmptyResource      bool   `json:"empty_resource"`
-	PodCount           int    `json:"pod_count"`
-	ServiceCount       int    `json:"service_count"`
-	DeploymentCount    int    `json:"deployment_count"`
-	ReplicasetCount    int    `json:"replicaset_count"`
-	StatefulsetCount   int    `json:"statefulset_count"`
-	DaemonsetCount     int    `json:"daemonset_count"`
-	JobCount           int    `json:"job_count"`
-	SecretCount        int    `json:"secret_count"`
-	ConfigMapCount     int    `json:"config_map_count"`
+	Name                     string `json:"name"`
+	EmptyResourceQuota       bool   `json:"empty_resource_quota"`
+	EmptyResource            bool   `json:"empty_resource"`
+	NetworkPolicyCount       int    `json:"network_policy_count"`
+	EmptyNetworkPolicy       bool   `json:"empty_network_policy"`
+	DefaultDenyNetworkPolicy bool   `json:"default_deny_network_policy"`
+	PodCount                 int    `json:"pod_count"`
+	ServiceCount             int    `json:"service_count"`
+	DeploymentCount          int    `json:"deployment_count"`
+	ReplicasetCount          int    `json:"replicaset_count"`
+	StatefulsetCount         int    `json:"statefulset_count"`
+	DaemonsetCount           int    `json:"daemonset_count"`
+	JobCount                 int    `json:"job_count"`
+	SecretCount              int    `json:"secret_count"`
+	ConfigMapCount           int    `json:"config_map_count"`
+	StaleReplicasetCount     int    `json:"stale_replicaset_count"`
+	UnusedSecretCount        int    `json:"unused_secret_count"`
+	UnusedConfigMapCount     int    `json:"unused_config_map_count"`
 }
 
 type PersistentVolumeClaim struct {
-	Name  string `json:"name"`
-	State string `json:"state"`
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace"`
+	State               string `json:"state"`
+	StorageClass        string `json:"storage_class"`
+	VolumeName          string `json:"volume_name"`
+	MissingStorageClass bool   `json:"missing_storage_class"`
+}
+
+type PersistentVolume struct {
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	StorageClass string `json:"storage_class"`
+	Claim        string `json:"claim"`
 }
 
 type Service struct {
-	Name           string `json:"name"`
-	Namespace      string `json:"namespace"`
-	EmptyEndpoints bool   `json:"empty_endpoints"`
+	Name                   string `json:"name"`
+	Namespace              string `json:"namespace"`
+	Type                   string `json:"type"`
+	EmptyEndpoints         bool   `json:"empty_endpoints"`
+	NoMatchingPods         bool   `json:"no_matching_pods"`
+	MissingTargetPort      bool   `json:"missing_target_port"`
+	PendingLoadBalancer    bool   `json:"pending_load_balancer"`
+	UnresolvedExternalName bool   `json:"unresolved_external_name"`
 }
 
 type Ingress struct {
-	Name          string `json:"name"`
-	Namespace     string `json:"namespace"`
-	DuplicatePath bool   `json:"duplicate_path"`
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace"`
+	DuplicatePath       bool   `json:"duplicate_path"`
+	DuplicateHost       bool   `json:"duplicate_host"`
+	MissingBackend      bool   `json:"missing_backend"`
+	MissingTLSSecret    bool   `json:"missing_tls_secret"`
+	MissingIngressClass bool   `json:"missing_ingress_class"`
+	ExpiringCertificate bool   `json:"expiring_certificate"`
+	CertificateNotAfter string `json:"certificate_not_after"`
+}
+
+type Security struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Issues    []string `json:"issues"`
+}
+
+type Image struct {
+	Name     string   `json:"name"`
+	ImageIDs []string `json:"image_ids"`
+	PodCount int      `json:"pod_count"`
+}
+
+type HelmRelease struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Revision        int    `json:"revision"`
+	Status          string `json:"status"`
+	Chart           string `json:"chart"`
+	ChartVersion    string `json:"chart_version"`
+	AppVersion      string `json:"app_version"`
+	SupersededCount int    `json:"superseded_count"`
 }
 
 type Pod struct {
@@ -119,9 +210,10 @@ type Status struct {
 }
 
 type Condition struct {
-	Type   string `json:"type"`
-	Status string `json:"status"`
-	Reason string `json:"reason"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
 }
 
 type Command struct {
@@ -169,10 +261,17 @@ func NewClusterResource() *ClusterResource {
 			Job:         []*WorkloadData{},
 			Cronjob:     []*WorkloadData{},
 		},
-		Namespace:   []*Namespace{},
-		Service:     []*Service{},
-		Ingress:     []*Ingress{},
-		Inspections: []*Inspection{},
+		HorizontalPodAutoscaler: []*HorizontalPodAutoscaler{},
+		PodDisruptionBudget:     []*PodDisruptionBudget{},
+		Namespace:               []*Namespace{},
+		PersistentVolumeClaim:   []*PersistentVolumeClaim{},
+		PersistentVolume:        []*PersistentVolume{},
+		Service:                 []*Service{},
+		Ingress:                 []*Ingress{},
+		Security:                []*Security{},
+		Image:                   []*Image{},
+		HelmRelease:             []*HelmRelease{},
+		Inspections:             []*Inspection{},
 	}
 }
 
@@ -198,6 +297,14 @@ func NewWorkloadDatas() []*WorkloadData {
 	return []*WorkloadData{}
 }
 
+func NewHorizontalPodAutoscalers() []*HorizontalPodAutoscaler {
+	return []*HorizontalPodAutoscaler{}
+}
+
+func NewPodDisruptionBudgets() []*PodDisruptionBudget {
+	return []*PodDisruptionBudget{}
+}
+
 func NewNamespaces() []*Namespace {
 	return []*Namespace{}
 }
@@ -206,6 +313,10 @@ func NewPersistentVolumeClaims() []*PersistentVolumeClaim {
 	return []*PersistentVolumeClaim{}
 }
 
+func NewPersistentVolumes() []*PersistentVolume {
+	return []*PersistentVolume{}
+}
+
 func NewServices() []*Service {
 	return []*Service{}
 }
@@ -214,6 +325,18 @@ func NewIngress() []*Ingress {
 	return []*Ingress{}
 }
 
+func NewSecurities() []*Security {
+	return []*Security{}
+}
+
+func NewImages() []*Image {
+	return []*Image{}
+}
+
+func NewHelmReleases() []*HelmRelease {
+	return []*HelmRelease{}
+}
+
 func NewInspections() []*Inspection {
 	return []*Inspection{}
 }
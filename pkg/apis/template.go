@@ -1,5 +1,7 @@
 package apis
 
+import "encoding/json"
+
 type Template struct {
 	ID               string              `json:"id"`
 	Name             string              `json:"name"`
@@ -7,17 +9,43 @@ type Template struct {
 }
 
 type KubernetesConfig struct {
-	Enable                bool                   `json:"enable"`
-	ClusterID             string                 `json:"cluster_id"`
-	ClusterName           string                 `json:"cluster_name"`
-	ClusterCoreConfig     *ClusterCoreConfig     `json:"cluster_core_config"`
-	ClusterNodeConfig     *ClusterNodeConfig     `json:"cluster_node_config"`
-	ClusterResourceConfig *ClusterResourceConfig `json:"cluster_resource_config"`
+	Enable                bool                      `json:"enable"`
+	ClusterID             string                    `json:"cluster_id"`
+	ClusterName           string                    `json:"cluster_name"`
+	ClusterCoreConfig     *ClusterCoreConfig        `json:"cluster_core_config"`
+	ClusterNodeConfig     *ClusterNodeConfig        `json:"cluster_node_config"`
+	ClusterResourceConfig *ClusterResourceConfig    `json:"cluster_resource_config"`
+	CheckerConfig         map[string]*CheckerConfig `json:"checker_config"`
+}
+
+// CheckerConfig 覆盖检查项的模板配置，enable 为空时按模板配置执行，config 的格式由检查项的 ConfigSchema 决定
+type CheckerConfig struct {
+	Enable *bool           `json:"enable"`
+	Config json.RawMessage `json:"config"`
 }
 
 type ClusterCoreConfig struct {
-	//EtcdHealthCheck
-	//APIServerHealthCheck
+	APIServerHealthCheck         bool               `json:"api_server_health_check"`
+	EtcdHealthCheck              bool               `json:"etcd_health_check"`
+	SchedulerHealthCheck         bool               `json:"scheduler_health_check"`
+	ControllerManagerHealthCheck bool               `json:"controller_manager_health_check"`
+	CertificateConfig            *CertificateConfig `json:"certificate_config"`
+	WebhookConfig                *WebhookConfig     `json:"webhook_config"`
+	APIServiceHealthCheck        bool               `json:"api_service_health_check"`
+}
+
+type WebhookConfig struct {
+	Enable bool `json:"enable"`
+	// 超过该超时时间的 Webhook 会拖慢所有匹配的 API 请求
+	MaxTimeoutSeconds int32 `json:"max_timeout_seconds"`
+}
+
+type CertificateConfig struct {
+	Enable       bool `json:"enable"`
+	WarningDays  int  `json:"warning_days"`
+	CriticalDays int  `json:"critical_days"`
+	// 通过 inspection-agent 检查的节点证书目录
+	NodePaths []string `json:"node_paths"`
 }
 
 type ClusterNodeConfig struct {
@@ -25,30 +53,137 @@ type ClusterNodeConfig struct {
 }
 
 type ClusterResourceConfig struct {
-	WorkloadConfig  *WorkloadConfig  `json:"workload_config"`
-	NamespaceConfig *NamespaceConfig `json:"namespace_config"`
-	ServiceConfig   *ServiceConfig   `json:"service_config"`
-	IngressConfig   *IngressConfig   `json:"ingress_config"`
+	WorkloadConfig                *WorkloadConfig                `json:"workload_config"`
+	NamespaceConfig               *NamespaceConfig               `json:"namespace_config"`
+	PersistentVolumeClaimConfig   *PersistentVolumeClaimConfig   `json:"persistent_volume_claim_config"`
+	PodConfig                     *PodConfig                     `json:"pod_config"`
+	EventConfig                   *EventConfig                   `json:"event_config"`
+	SecurityConfig                *SecurityConfig                `json:"security_config"`
+	ImageConfig                   *ImageConfig                   `json:"image_config"`
+	HorizontalPodAutoscalerConfig *HorizontalPodAutoscalerConfig `json:"horizontal_pod_autoscaler_config"`
+	PodDisruptionBudgetConfig     *PodDisruptionBudgetConfig     `json:"pod_disruption_budget_config"`
+	RBACConfig                    *RBACConfig                    `json:"rbac_config"`
+	NetworkPolicyConfig           *NetworkPolicyConfig           `json:"network_policy_config"`
+	ServiceConfig                 *ServiceConfig                 `json:"service_config"`
+	IngressConfig                 *IngressConfig                 `json:"ingress_config"`
+	FinalizerConfig               *FinalizerConfig               `json:"finalizer_config"`
+	CleanupConfig                 *CleanupConfig                 `json:"cleanup_config"`
+	HelmConfig                    *HelmConfig                    `json:"helm_config"`
+	ResourcePolicyConfig          *ResourcePolicyConfig          `json:"resource_policy_config"`
 }
 
 type NamespaceConfig struct {
 	Enable bool `json:"enable"`
 }
 
+type PersistentVolumeClaimConfig struct {
+	Enable bool `json:"enable"`
+}
+
+type PodConfig struct {
+	Enable           bool `json:"enable"`
+	RestartThreshold int  `json:"restart_threshold"`
+	PendingMinutes   int  `json:"pending_minutes"`
+}
+
+type EventConfig struct {
+	Enable            bool     `json:"enable"`
+	LookbackHours     int      `json:"lookback_hours"`
+	IncludeNamespaces []string `json:"include_namespaces"`
+	ExcludeNamespaces []string `json:"exclude_namespaces"`
+}
+
+type SecurityConfig struct {
+	Enable bool `json:"enable"`
+	// 不做安全检查的系统命名空间
+	AllowNamespaces []string `json:"allow_namespaces"`
+	// 需要自动挂载 ServiceAccount token 的命名空间
+	TokenNamespaces []string `json:"token_namespaces"`
+}
+
+type ImageConfig struct {
+	Enable bool `json:"enable"`
+	// 允许使用的镜像仓库，为空时不检查
+	AllowRegistries []string `json:"allow_registries"`
+}
+
+type HorizontalPodAutoscalerConfig struct {
+	Enable bool `json:"enable"`
+}
+
+type PodDisruptionBudgetConfig struct {
+	Enable bool `json:"enable"`
+}
+
+type RBACConfig struct {
+	Enable bool `json:"enable"`
+	// 允许绑定 cluster-admin 的主体，格式为 User/name、Group/name 或 ServiceAccount/namespace/name
+	AllowSubjects []string `json:"allow_subjects"`
+}
+
+type NetworkPolicyConfig struct {
+	Enable            bool     `json:"enable"`
+	ExcludeNamespaces []string `json:"exclude_namespaces"`
+}
+
 type ServiceConfig struct {
 	Enable bool `json:"enable"`
 }
 
 type IngressConfig struct {
 	Enable bool `json:"enable"`
+	// TLS 证书剩余有效天数小于该值时告警
+	CertificateExpiryDays int `json:"certificate_expiry_days"`
+}
+
+type FinalizerConfig struct {
+	Enable bool `json:"enable"`
+	// 资源处于删除中超过该时间时告警
+	ThresholdMinutes int `json:"threshold_minutes"`
+}
+
+type CleanupConfig struct {
+	Enable bool `json:"enable"`
+	// 每个 Deployment 保留的副本数为 0 的 ReplicaSet 数量
+	RevisionHistoryLimit int `json:"revision_history_limit"`
+	// 没有 Endpoint 的 Service 创建超过该天数时告警
+	EmptyServiceDays  int      `json:"empty_service_days"`
+	ExcludeNamespaces []string `json:"exclude_namespaces"`
+}
+
+type HelmConfig struct {
+	Enable bool `json:"enable"`
+	// 每个 release 保留的 superseded 版本数量
+	MaxHistory int `json:"max_history"`
+}
+
+type ResourcePolicyConfig struct {
+	Enable            bool     `json:"enable"`
+	ExcludeNamespaces []string `json:"exclude_namespaces"`
+	// requests 超过实际用量的倍数时告警，为 0 时不检查
+	RequestUsageRatio float64 `json:"request_usage_ratio"`
 }
 
 type WorkloadConfig struct {
-	Deployment  []*WorkloadDetailConfig `json:"deployment"`
-	Statefulset []*WorkloadDetailConfig `json:"statefulset"`
-	Daemonset   []*WorkloadDetailConfig `json:"daemonset"`
-	Job         []*WorkloadDetailConfig `json:"job"`
-	Cronjob     []*WorkloadDetailConfig `json:"cronjob"`
+	Deployment    []*WorkloadDetailConfig `json:"deployment"`
+	Statefulset   []*WorkloadDetailConfig `json:"statefulset"`
+	Daemonset     []*WorkloadDetailConfig `json:"daemonset"`
+	Job           []*WorkloadDetailConfig `json:"job"`
+	Cronjob       []*WorkloadDetailConfig `json:"cronjob"`
+	JobConfig     *JobConfig              `json:"job_config"`
+	CronjobConfig *CronjobConfig          `json:"cronjob_config"`
+}
+
+type JobConfig struct {
+	// Job 运行超过该时间时告警，为 0 时不检查
+	MaxRunningMinutes int `json:"max_running_minutes"`
+}
+
+type CronjobConfig struct {
+	// 最近一次成功执行距今超过多少个调度周期时告警
+	MissedSchedules int `json:"missed_schedules"`
+	// 同时处于运行中的 Job 超过该数量时告警
+	MaxActiveJobs int `json:"max_active_jobs"`
 }
 
 type WorkloadDetailConfig struct {
@@ -58,8 +193,25 @@ type WorkloadDetailConfig struct {
 }
 
 type NodeConfig struct {
-	Names    []string         `json:"names"`
-	Commands []*CommandConfig `json:"commands"`
+	Names      []string             `json:"names"`
+	Commands   []*CommandConfig     `json:"commands"`
+	Thresholds *NodeThresholdConfig `json:"thresholds"`
+}
+
+// NodeThresholdConfig 节点资源占可分配资源的百分比阈值
+type NodeThresholdConfig struct {
+	LimitsCPU      *ThresholdConfig `json:"limits_cpu"`
+	LimitsMemory   *ThresholdConfig `json:"limits_memory"`
+	RequestsCPU    *ThresholdConfig `json:"requests_cpu"`
+	RequestsMemory *ThresholdConfig `json:"requests_memory"`
+	RequestsPods   *ThresholdConfig `json:"requests_pods"`
+	UsageCPU       *ThresholdConfig `json:"usage_cpu"`
+	UsageMemory    *ThresholdConfig `json:"usage_memory"`
+}
+
+type ThresholdConfig struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
 }
 
 type CommandConfig struct {
@@ -67,6 +219,38 @@ type CommandConfig struct {
 	Command     string `json:"command"`
 }
 
+func NewJobConfig() *JobConfig {
+	return &JobConfig{
+		MaxRunningMinutes: 60,
+	}
+}
+
+func NewCronjobConfig() *CronjobConfig {
+	return &CronjobConfig{
+		MissedSchedules: 3,
+		MaxActiveJobs:   1,
+	}
+}
+
+func NewThresholdConfig() *ThresholdConfig {
+	return &ThresholdConfig{
+		Warning:  80,
+		Critical: 90,
+	}
+}
+
+func NewNodeThresholdConfig() *NodeThresholdConfig {
+	return &NodeThresholdConfig{
+		LimitsCPU:      NewThresholdConfig(),
+		LimitsMemory:   NewThresholdConfig(),
+		RequestsCPU:    NewThresholdConfig(),
+		RequestsMemory: NewThresholdConfig(),
+		RequestsPods:   NewThresholdConfig(),
+		UsageCPU:       NewThresholdConfig(),
+		UsageMemory:    NewThresholdConfig(),
+	}
+}
+
 func NewTemplate() *Template {
 	return &Template{
 		KubernetesConfig: []*KubernetesConfig{},
@@ -82,7 +266,24 @@ func NewKubernetesConfig() []*KubernetesConfig {
 }
 
 func NewClusterCoreConfig() *ClusterCoreConfig {
-	return &ClusterCoreConfig{}
+	return &ClusterCoreConfig{
+		CertificateConfig: NewCertificateConfig(),
+		WebhookConfig:     NewWebhookConfig(),
+	}
+}
+
+func NewWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		MaxTimeoutSeconds: 15,
+	}
+}
+
+func NewCertificateConfig() *CertificateConfig {
+	return &CertificateConfig{
+		WarningDays:  30,
+		CriticalDays: 7,
+		NodePaths:    []string{"/etc/kubernetes/ssl", "/var/lib/rancher"},
+	}
 }
 
 func NewClusterNodeConfig() *ClusterNodeConfig {
@@ -93,9 +294,22 @@ func NewClusterNodeConfig() *ClusterNodeConfig {
 
 func NewClusterResourceConfig() *ClusterResourceConfig {
 	return &ClusterResourceConfig{
-		WorkloadConfig:  &WorkloadConfig{},
-		NamespaceConfig: &NamespaceConfig{},
-		ServiceConfig:   &ServiceConfig{},
-		IngressConfig:   &IngressConfig{},
+		WorkloadConfig:                &WorkloadConfig{},
+		NamespaceConfig:               &NamespaceConfig{},
+		PersistentVolumeClaimConfig:   &PersistentVolumeClaimConfig{},
+		PodConfig:                     &PodConfig{},
+		EventConfig:                   &EventConfig{},
+		SecurityConfig:                &SecurityConfig{},
+		ImageConfig:                   &ImageConfig{},
+		HorizontalPodAutoscalerConfig: &HorizontalPodAutoscalerConfig{},
+		PodDisruptionBudgetConfig:     &PodDisruptionBudgetConfig{},
+		RBACConfig:                    &RBACConfig{},
+		NetworkPolicyConfig:           &NetworkPolicyConfig{},
+		ServiceConfig:                 &ServiceConfig{},
+		IngressConfig:                 &IngressConfig{},
+		FinalizerConfig:               &FinalizerConfig{},
+		CleanupConfig:                 &CleanupConfig{},
+		HelmConfig:                    &HelmConfig{},
+		ResourcePolicyConfig:          &ResourcePolicyConfig{},
 	}
 }
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var apiServiceResource = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+type apiServiceChecker struct{}
+
+func (c *apiServiceChecker) Name() string { return "api_service" }
+
+func (c *apiServiceChecker) Category() Category { return CategoryCore }
+
+func (c *apiServiceChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterCoreConfig != nil && config.ClusterCoreConfig.APIServiceHealthCheck
+}
+
+func (c *apiServiceChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterCoreConfig
+}
+
+func (c *apiServiceChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetAPIServices(ctx, client)
+}
+
+// GetAPIServices 检查聚合 API，APIService 不可用时对应 API 组的请求和资源发现都会失败
+func GetAPIServices(ctx context.Context, client *apis.Client) ([]*apis.Inspection, error) {
+	coreInspections := apis.NewInspections()
+
+	apiServiceList, err := client.DynamicClient.Resource(apiServiceResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range apiServiceList.Items {
+		conditions, _, err := unstructured.NestedSlice(a.Object, "status", "conditions")
+		if err != nil {
+			continue
+		}
+
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != "Available" || condition["status"] == "True" {
+				continue
+			}
+
+			coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("APIService %s 不可用", a.GetName()), fmt.Sprintf("%v: %v", condition["reason"], condition["message"]), 2))
+		}
+	}
+
+	return coreInspections, nil
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type availabilityChecker struct{}
+
+func (c *availabilityChecker) Name() string { return "availability" }
+
+func (c *availabilityChecker) Category() Category { return CategoryResource }
+
+func (c *availabilityChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.PodDisruptionBudgetConfig != nil && config.ClusterResourceConfig.PodDisruptionBudgetConfig.Enable
+}
+
+func (c *availabilityChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.PodDisruptionBudgetConfig
+}
+
+func (c *availabilityChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	pdbs, inspections, err := GetAvailability(ctx, client, config.ClusterResourceConfig.WorkloadConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.PodDisruptionBudget = pdbs
+	return inspections, nil
+}
+
+type replicatedWorkload struct {
+	kind           string
+	name           string
+	namespace      string
+	replicas       int32
+	selector       *metav1.LabelSelector
+	templateLabels labels.Set
+}
+
+// GetAvailability 检查 PodDisruptionBudget 以及多副本工作负载在节点或可用区故障时的可用性
+func GetAvailability(ctx context.Context, client *apis.Client, workloadConfig *apis.WorkloadConfig) ([]*apis.PodDisruptionBudget, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	pdbs := apis.NewPodDisruptionBudgets()
+
+	pdbList, err := client.Clientset.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodeList, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodeZones := make(map[string]string)
+	clusterZones := make(map[string]bool)
+	for _, n := range nodeList.Items {
+		zone := n.Labels[corev1.LabelTopologyZone]
+		nodeZones[n.Name] = zone
+		if zone != "" {
+			clusterZones[zone] = true
+		}
+	}
+
+	pdbSelectors := make(map[string][]labels.Selector)
+	for _, p := range pdbList.Items {
+		var minAvailable, maxUnavailable string
+		if p.Spec.MinAvailable != nil {
+			minAvailable = p.Spec.MinAvailable.String()
+		}
+		if p.Spec.MaxUnavailable != nil {
+			maxUnavailable = p.Spec.MaxUnavailable.String()
+		}
+
+		pdbs = append(pdbs, &apis.PodDisruptionBudget{
+			Name:               p.Name,
+			Namespace:          p.Namespace,
+			MinAvailable:       minAvailable,
+			MaxUnavailable:     maxUnavailable,
+			ExpectedPods:       p.Status.ExpectedPods,
+			DisruptionsAllowed: p.Status.DisruptionsAllowed,
+		})
+
+		selector, err := metav1.LabelSelectorAsSelector(p.Spec.Selector)
+		if err != nil {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PDB %s 的 selector 无效", p.Namespace, p.Name), err.Error(), 1))
+			continue
+		}
+		pdbSelectors[p.Namespace] = append(pdbSelectors[p.Namespace], selector)
+
+		if len(getSelectedPods(podList.Items, p.Namespace, selector)) == 0 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PDB %s 没有匹配的 Pod", p.Namespace, p.Name), fmt.Sprintf("PDB %s 的 selector %s 没有匹配任何 Pod", p.Name, selector.String()), 1))
+			continue
+		}
+
+		if p.Status.DisruptionsAllowed == 0 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PDB %s 不允许任何中断", p.Namespace, p.Name), fmt.Sprintf("PDB %s 当前允许中断数为 0，节点排空和集群升级将被阻塞", p.Name), 2))
+		}
+	}
+
+	workloads, err := getReplicatedWorkloads(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	criticalWorkloads := getCriticalWorkloads(workloadConfig)
+	for _, w := range workloads {
+		if w.replicas == 1 && criticalWorkloads[fmt.Sprintf("%s/%s/%s", w.namespace, w.kind, w.name)] {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下关键工作负载 %s %s 只有一个副本", w.namespace, w.kind, w.name), fmt.Sprintf("%s %s 所在节点故障时服务将中断", w.kind, w.name), 2))
+		}
+
+		if w.replicas <= 1 {
+			continue
+		}
+
+		var hasPDB bool
+		// policy/v1 中空 selector 匹配命名空间下所有 Pod，nil selector 已被转换为 labels.Nothing()
+		for _, selector := range pdbSelectors[w.namespace] {
+			if selector.Matches(w.templateLabels) {
+				hasPDB = true
+				break
+			}
+		}
+		if !hasPDB {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 没有 PDB", w.namespace, w.kind, w.name), fmt.Sprintf("%s %s 有 %d 个副本但没有配置 PodDisruptionBudget", w.kind, w.name, w.replicas), 1))
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(w.selector)
+		if err != nil {
+			continue
+		}
+
+		nodes := make(map[string]bool)
+		zones := make(map[string]bool)
+		for _, pod := range getSelectedPods(podList.Items, w.namespace, selector) {
+			if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+				continue
+			}
+			nodes[pod.Spec.NodeName] = true
+			if zone := nodeZones[pod.Spec.NodeName]; zone != "" {
+				zones[zone] = true
+			}
+		}
+
+		if len(nodes) == 1 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 的副本都运行在同一节点", w.namespace, w.kind, w.name), fmt.Sprintf("%s %s 的 %d 个副本都运行在同一节点，该节点故障时服务将中断", w.kind, w.name, w.replicas), 2))
+		} else if len(nodes) > 1 && len(zones) == 1 && len(clusterZones) > 1 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 的副本都运行在同一可用区", w.namespace, w.kind, w.name), fmt.Sprintf("%s %s 的 %d 个副本都运行在同一可用区，该可用区故障时服务将中断", w.kind, w.name, w.replicas), 1))
+		}
+	}
+
+	return pdbs, resourceInspections, nil
+}
+
+func getReplicatedWorkloads(ctx context.Context, client *apis.Client) ([]*replicatedWorkload, error) {
+	var workloads []*replicatedWorkload
+
+	deploymentList, err := client.Clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range deploymentList.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+
+		workloads = append(workloads, &replicatedWorkload{
+			kind:           "Deployment",
+			name:           d.Name,
+			namespace:      d.Namespace,
+			replicas:       replicas,
+			selector:       d.Spec.Selector,
+			templateLabels: labels.Set(d.Spec.Template.Labels),
+		})
+	}
+
+	statefulSetList, err := client.Clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statefulSetList.Items {
+		replicas := int32(1)
+		if s.Spec.Replicas != nil {
+			replicas = *s.Spec.Replicas
+		}
+
+		workloads = append(workloads, &replicatedWorkload{
+			kind:           "StatefulSet",
+			name:           s.Name,
+			namespace:      s.Namespace,
+			replicas:       replicas,
+			selector:       s.Spec.Selector,
+			templateLabels: labels.Set(s.Spec.Template.Labels),
+		})
+	}
+
+	return workloads, nil
+}
+
+func getSelectedPods(pods []corev1.Pod, namespace string, selector labels.Selector) []*corev1.Pod {
+	var result []*corev1.Pod
+	for i := range pods {
+		if pods[i].Namespace == namespace && selector.Matches(labels.Set(pods[i].Labels)) {
+			result = append(result, &pods[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"inspection-server/pkg/apis"
+	"inspection-server/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	defaultCertificateExpiryDays = 30
+	// inspection-agent 容器中宿主机根目录的挂载路径
+	agentHostRoot = "/inspection"
+)
+
+// parseCertificates 解析 PEM 格式的证书链，第一个为叶子证书
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// getCertificateInspection 证书已过期或剩余天数小于 criticalDays 为等级 3，小于 warningDays 为等级 1
+func getCertificateInspection(name string, notAfter time.Time, warningDays, criticalDays int) *apis.Inspection {
+	days := int(math.Floor(time.Until(notAfter).Hours() / 24))
+	if days < 0 {
+		return apis.NewInspection(fmt.Sprintf("%s 证书已过期", name), fmt.Sprintf("证书已于 %s 过期", notAfter.Format(time.DateTime)), 3)
+	}
+
+	if days < criticalDays {
+		return apis.NewInspection(fmt.Sprintf("%s 证书即将过期", name), fmt.Sprintf("证书将于 %s 过期，剩余 %d 天", notAfter.Format(time.DateTime), days), 3)
+	}
+
+	if days < warningDays {
+		return apis.NewInspection(fmt.Sprintf("%s 证书即将过期", name), fmt.Sprintf("证书将于 %s 过期，剩余 %d 天", notAfter.Format(time.DateTime), days), 1)
+	}
+
+	return nil
+}
+
+type certificateChecker struct{}
+
+func (c *certificateChecker) Name() string { return "certificate" }
+
+func (c *certificateChecker) Category() Category { return CategoryCore }
+
+func (c *certificateChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterCoreConfig != nil && config.ClusterCoreConfig.CertificateConfig != nil && config.ClusterCoreConfig.CertificateConfig.Enable
+}
+
+func (c *certificateChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterCoreConfig.CertificateConfig
+}
+
+func (c *certificateChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetCertificates(ctx, client, config.ClusterID, config.ClusterCoreConfig.CertificateConfig)
+}
+
+// GetCertificates 检查 TLS Secret、kubeconfig、Webhook caBundle 以及节点上的证书
+func GetCertificates(ctx context.Context, client *apis.Client, clusterID string, certificateConfig *apis.CertificateConfig) ([]*apis.Inspection, error) {
+	coreInspections := apis.NewInspections()
+
+	warningDays := certificateConfig.WarningDays
+	if warningDays <= 0 {
+		warningDays = defaultCertificateExpiryDays
+	}
+	criticalDays := certificateConfig.CriticalDays
+
+	// 证书链和 caBundle 中可能包含多个证书，需要逐个检查
+	addCertificate := func(name string, data []byte) {
+		certificates, err := parseCertificates(data)
+		if err != nil {
+			return
+		}
+
+		for _, certificate := range certificates {
+			certificateName := name
+			if len(certificates) > 1 {
+				certificateName = fmt.Sprintf("%s 中 %s", name, certificate.Subject.CommonName)
+			}
+
+			if inspection := getCertificateInspection(certificateName, certificate.NotAfter, warningDays, criticalDays); inspection != nil {
+				coreInspections = append(coreInspections, inspection)
+			}
+		}
+	}
+
+	secretList, err := client.Clientset.CoreV1().Secrets("").List(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range secretList.Items {
+		addCertificate(fmt.Sprintf("命名空间 %s 下 Secret %s", s.Namespace, s.Name), s.Data[corev1.TLSCertKey])
+	}
+
+	kubeconfig, err := clientcmd.LoadFromFile(common.WriteKubeconfigPath + clusterID)
+	if err == nil {
+		for name, cluster := range kubeconfig.Clusters {
+			addCertificate(fmt.Sprintf("kubeconfig 集群 %s 的 CA", name), cluster.CertificateAuthorityData)
+		}
+		for name, authInfo := range kubeconfig.AuthInfos {
+			addCertificate(fmt.Sprintf("kubeconfig 用户 %s 的客户端", name), authInfo.ClientCertificateData)
+		}
+	}
+
+	validatingWebhookList, err := client.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range validatingWebhookList.Items {
+		for _, webhook := range w.Webhooks {
+			addCertificate(fmt.Sprintf("ValidatingWebhookConfiguration %s 中 Webhook %s 的 caBundle", w.Name, webhook.Name), webhook.ClientConfig.CABundle)
+		}
+	}
+
+	mutatingWebhookList, err := client.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range mutatingWebhookList.Items {
+		for _, webhook := range w.Webhooks {
+			addCertificate(fmt.Sprintf("MutatingWebhookConfiguration %s 中 Webhook %s 的 caBundle", w.Name, webhook.Name), webhook.ClientConfig.CABundle)
+		}
+	}
+
+	if len(certificateConfig.NodePaths) == 0 {
+		return coreInspections, nil
+	}
+
+	set := labels.Set(map[string]string{"name": "inspection-agent"})
+	podList, err := client.Clientset.CoreV1().Pods(common.InspectionNamespace).List(ctx, metav1.ListOptions{LabelSelector: set.String()})
+	if err != nil {
+		logrus.Warnf("Failed to list inspection-agent pods, skip node certificates: %v\n", err)
+		return coreInspections, nil
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		stdout, _, err := ExecToPodThroughAPI(client.Clientset, client.Config, "/bin/sh", []string{"-c", getNodeCertificateScript(certificateConfig.NodePaths)}, pod.Namespace, pod.Name, "inspection-agent-container")
+		if err != nil {
+			coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("Node %s 证书检查失败", pod.Spec.NodeName), fmt.Sprintf("通过 inspection-agent Pod %s 读取节点证书失败: %v", pod.Name, err), 1))
+			continue
+		}
+
+		for path, data := range parseNodeCertificateOutput(stdout) {
+			addCertificate(fmt.Sprintf("Node %s 上的证书 %s", pod.Spec.NodeName, path), data)
+		}
+	}
+
+	return coreInspections, nil
+}
+
+// getNodeCertificateScript 宿主机根目录挂载在 agent 容器的 /inspection 下，跳过 containerd 等数据目录
+func getNodeCertificateScript(paths []string) string {
+	var hostPaths []string
+	for _, p := range paths {
+		hostPaths = append(hostPaths, agentHostRoot+p)
+	}
+
+	return fmt.Sprintf(`find %s -maxdepth 5 -name containerd -prune -o -type f \( -name '*.crt' -o -name '*.pem' \) ! -name '*key*' -print 2>/dev/null | while read f; do echo "### $f"; cat "$f"; done`, strings.Join(hostPaths, " "))
+}
+
+func parseNodeCertificateOutput(stdout string) map[string][]byte {
+	result := make(map[string][]byte)
+	for _, part := range strings.Split(stdout, "### ")[1:] {
+		path, data, _ := strings.Cut(part, "\n")
+		result[strings.TrimPrefix(strings.TrimSpace(path), agentHostRoot)] = []byte(data)
+	}
+	return result
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"inspection-server/pkg/apis"
+	"sync"
+)
+
+type Category string
+
+const (
+	CategoryCore     Category = "core"
+	CategoryNode     Category = "node"
+	CategoryResource Category = "resource"
+)
+
+// Checker 巡检检查项，Inspection 会依次执行模板中开启的检查项
+type Checker interface {
+	// Name 检查项名称，同时作为模板 checker_config 中的键，checker_config 中 enable 为 false 时不执行该检查项
+	Name() string
+	Category() Category
+	// Enabled 根据集群模板配置判断是否执行该检查项
+	Enabled(config *apis.KubernetesConfig) bool
+	// ConfigSchema 返回模板中该检查项配置结构体的指针，checker_config 中的 config 会解析到其中覆盖模板配置，
+	// 只在 Enabled 返回 true 后调用，没有配置的检查项返回 nil
+	ConfigSchema(config *apis.KubernetesConfig) interface{}
+	// Run 执行检查，检查数据写入 kubernetes 的对应部分，返回巡检结果
+	Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error)
+}
+
+// DependentChecker 需要读取其他检查项写入 kubernetes 的数据，RunCheckers 会先执行其依赖的检查项
+type DependentChecker interface {
+	Checker
+	Dependencies() []string
+}
+
+var (
+	checkers     []Checker
+	checkerMutex sync.RWMutex
+)
+
+func init() {
+	for _, c := range []Checker{
+		&controlPlaneChecker{},
+		&certificateChecker{},
+		&webhookChecker{},
+		&apiServiceChecker{},
+		&nodeChecker{},
+		&workloadChecker{},
+		&horizontalPodAutoscalerChecker{},
+		&availabilityChecker{},
+		&namespaceChecker{},
+		&networkPolicyChecker{},
+		&cleanupChecker{},
+		&storageChecker{},
+		&finalizerChecker{},
+		&podChecker{},
+		&eventChecker{},
+		&securityChecker{},
+		&resourcePolicyChecker{},
+		&imageChecker{},
+		&helmChecker{},
+		&rbacChecker{},
+		&serviceChecker{},
+		&ingressChecker{},
+	} {
+		if err := RegisterChecker(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func RegisterChecker(checker Checker) error {
+	checkerMutex.Lock()
+	defer checkerMutex.Unlock()
+
+	for _, c := range checkers {
+		if c.Name() == checker.Name() {
+			return fmt.Errorf("checker %s already registered", checker.Name())
+		}
+	}
+
+	checkers = append(checkers, checker)
+	return nil
+}
+
+func GetCheckers() []Checker {
+	checkerMutex.RLock()
+	defer checkerMutex.RUnlock()
+
+	result := make([]Checker, len(checkers))
+	copy(result, checkers)
+	return result
+}
+
+func RunCheckers(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) string {
+	var errMessage string
+	for _, c := range getOrderedCheckers(GetCheckers()) {
+		// checker_config 只能关闭检查项，开启检查项需要模板中对应的配置，否则 Run 中的配置为空
+		if !c.Enabled(config) {
+			continue
+		}
+		if checkerConfig, ok := config.CheckerConfig[c.Name()]; ok && checkerConfig != nil {
+			if checkerConfig.Enable != nil && !*checkerConfig.Enable {
+				continue
+			}
+
+			if schema := c.ConfigSchema(config); schema != nil && len(checkerConfig.Config) > 0 {
+				if err := json.Unmarshal(checkerConfig.Config, schema); err != nil {
+					errMessage += fmt.Sprintf("解析集群 %s %s 检查项配置时失败: %v\n", config.ClusterID, c.Name(), err)
+					continue
+				}
+			}
+		}
+
+		inspections, err := c.Run(ctx, client, config, kubernetes)
+		if err != nil {
+			errMessage += fmt.Sprintf("获取集群 %s %s 相关巡检信息时失败: %v\n", config.ClusterID, c.Name(), err)
+		}
+
+		switch c.Category() {
+		case CategoryCore:
+			kubernetes.ClusterCore.Inspections = append(kubernetes.ClusterCore.Inspections, inspections...)
+		case CategoryNode:
+			kubernetes.ClusterNode.Inspections = append(kubernetes.ClusterNode.Inspections, inspections...)
+		case CategoryResource:
+			kubernetes.ClusterResource.Inspections = append(kubernetes.ClusterResource.Inspections, inspections...)
+		}
+	}
+
+	return errMessage
+}
+
+// getOrderedCheckers 在保持注册顺序的前提下，将依赖的检查项排在前面
+func getOrderedCheckers(checkers []Checker) []Checker {
+	checkerMap := make(map[string]Checker)
+	for _, c := range checkers {
+		checkerMap[c.Name()] = c
+	}
+
+	var result []Checker
+	visited := make(map[string]bool)
+	var visit func(c Checker)
+	visit = func(c Checker) {
+		if visited[c.Name()] {
+			return
+		}
+		visited[c.Name()] = true
+
+		if d, ok := c.(DependentChecker); ok {
+			for _, name := range d.Dependencies() {
+				if dependency, ok := checkerMap[name]; ok {
+					visit(dependency)
+				}
+			}
+		}
+		result = append(result, c)
+	}
+
+	for _, c := range checkers {
+		visit(c)
+	}
+
+	return result
+}
+
+type nodeChecker struct{}
+
+func (c *nodeChecker) Name() string { return "node" }
+
+func (c *nodeChecker) Category() Category { return CategoryNode }
+
+func (c *nodeChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterNodeConfig != nil
+}
+
+func (c *nodeChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterNodeConfig
+}
+
+func (c *nodeChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	nodes, inspections, err := GetNodes(client, config.ClusterNodeConfig.NodeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterNode.Nodes = nodes
+	return inspections, nil
+}
+
+type workloadChecker struct{}
+
+func (c *workloadChecker) Name() string { return "workload" }
+
+func (c *workloadChecker) Category() Category { return CategoryResource }
+
+func (c *workloadChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.WorkloadConfig != nil
+}
+
+func (c *workloadChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.WorkloadConfig
+}
+
+func (c *workloadChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	workloads, inspections, err := GetWorkloads(client, config.ClusterResourceConfig.WorkloadConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Workloads = workloads
+	return inspections, nil
+}
+
+type horizontalPodAutoscalerChecker struct{}
+
+func (c *horizontalPodAutoscalerChecker) Name() string { return "horizontal_pod_autoscaler" }
+
+func (c *horizontalPodAutoscalerChecker) Category() Category { return CategoryResource }
+
+func (c *horizontalPodAutoscalerChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.HorizontalPodAutoscalerConfig != nil && config.ClusterResourceConfig.HorizontalPodAutoscalerConfig.Enable
+}
+
+func (c *horizontalPodAutoscalerChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.HorizontalPodAutoscalerConfig
+}
+
+func (c *horizontalPodAutoscalerChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	hpas, inspections, err := GetHorizontalPodAutoscalers(client)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.HorizontalPodAutoscaler = hpas
+	return inspections, nil
+}
+
+type namespaceChecker struct{}
+
+func (c *namespaceChecker) Name() string { return "namespace" }
+
+func (c *namespaceChecker) Category() Category { return CategoryResource }
+
+func (c *namespaceChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.NamespaceConfig != nil && config.ClusterResourceConfig.NamespaceConfig.Enable
+}
+
+func (c *namespaceChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.NamespaceConfig
+}
+
+func (c *namespaceChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	namespaces, inspections, err := GetNamespaces(client)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Namespace = namespaces
+	return inspections, nil
+}
+
+type serviceChecker struct{}
+
+func (c *serviceChecker) Name() string { return "service" }
+
+func (c *serviceChecker) Category() Category { return CategoryResource }
+
+func (c *serviceChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.ServiceConfig != nil && config.ClusterResourceConfig.ServiceConfig.Enable
+}
+
+func (c *serviceChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.ServiceConfig
+}
+
+func (c *serviceChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	services, inspections, err := GetServices(client)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Service = services
+	return inspections, nil
+}
+
+type ingressChecker struct{}
+
+func (c *ingressChecker) Name() string { return "ingress" }
+
+func (c *ingressChecker) Category() Category { return CategoryResource }
+
+func (c *ingressChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.IngressConfig != nil && config.ClusterResourceConfig.IngressConfig.Enable
+}
+
+func (c *ingressChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.IngressConfig
+}
+
+func (c *ingressChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	ingress, inspections, err := GetIngress(client, config.ClusterResourceConfig.IngressConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Ingress = ingress
+	return inspections, nil
+}
@@ -0,0 +1,380 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	defaultRevisionHistoryLimit = 10
+	defaultEmptyServiceDays     = 7
+	// Helm 3 以 Secret 保存 release 信息
+	helmReleaseSecretType corev1.SecretType = "helm.sh/release.v1"
+)
+
+type cleanupChecker struct{}
+
+func (c *cleanupChecker) Name() string { return "cleanup" }
+
+func (c *cleanupChecker) Category() Category { return CategoryResource }
+
+func (c *cleanupChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.CleanupConfig != nil && config.ClusterResourceConfig.CleanupConfig.Enable
+}
+
+func (c *cleanupChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.CleanupConfig
+}
+
+func (c *cleanupChecker) Dependencies() []string { return []string{"namespace"} }
+
+// Run 在命名空间巡检之后执行，将可清理的资源数量写入已有的命名空间数据
+func (c *cleanupChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetCleanup(ctx, client, config.ClusterResourceConfig.CleanupConfig, kubernetes.ClusterResource.Namespace)
+}
+
+// GetCleanup 检查可以清理的资源：多余的 ReplicaSet、未被引用的 ConfigMap 和 Secret、Released 状态的 PV、没有 TTL 的已完成 Job 以及长期没有 Endpoint 的 Service
+func GetCleanup(ctx context.Context, client *apis.Client, cleanupConfig *apis.CleanupConfig, namespaces []*apis.Namespace) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	revisionHistoryLimit := cleanupConfig.RevisionHistoryLimit
+	if revisionHistoryLimit <= 0 {
+		revisionHistoryLimit = defaultRevisionHistoryLimit
+	}
+	emptyServiceDays := cleanupConfig.EmptyServiceDays
+	if emptyServiceDays <= 0 {
+		emptyServiceDays = defaultEmptyServiceDays
+	}
+
+	namespaceData := make(map[string]*apis.Namespace)
+	for _, n := range namespaces {
+		namespaceData[n.Name] = n
+	}
+	isExcluded := func(namespace string) bool {
+		return slices.Contains(cleanupConfig.ExcludeNamespaces, namespace)
+	}
+
+	replicaSetList, err := client.Clientset.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// 按所属 Deployment 统计副本数为 0 的 ReplicaSet
+	staleReplicaSets := make(map[string][]string)
+	var deployments []string
+	for i := range replicaSetList.Items {
+		rs := &replicaSetList.Items[i]
+		if isExcluded(rs.Namespace) || rs.Spec.Replicas == nil || *rs.Spec.Replicas != 0 {
+			continue
+		}
+
+		owner := "ReplicaSet"
+		if o := metav1.GetControllerOf(rs); o != nil {
+			owner = fmt.Sprintf("%s %s", o.Kind, o.Name)
+		}
+		key := rs.Namespace + "/" + owner
+		if _, ok := staleReplicaSets[key]; !ok {
+			deployments = append(deployments, key)
+		}
+		staleReplicaSets[key] = append(staleReplicaSets[key], rs.Name)
+	}
+
+	for _, key := range deployments {
+		namespace, owner, _ := strings.Cut(key, "/")
+		count := len(staleReplicaSets[key])
+		if data, ok := namespaceData[namespace]; ok {
+			data.StaleReplicasetCount += count
+		}
+
+		if owner == "ReplicaSet" {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下存在副本数为 0 的独立 ReplicaSet", namespace), fmt.Sprintf("ReplicaSet: %s", strings.Join(staleReplicaSets[key], ", ")), 1))
+		} else if count > revisionHistoryLimit {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s 保留了过多的 ReplicaSet", namespace, owner), fmt.Sprintf("副本数为 0 的 ReplicaSet 有 %d 个，超过 %d 个", count, revisionHistoryLimit), 1))
+		}
+	}
+
+	configMaps, secrets, err := getReferencedConfigs(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	configMapList, err := client.Clientset.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	unusedConfigMaps := make(map[string][]string)
+	for _, c := range configMapList.Items {
+		// kube-root-ca.crt 由 Kubernetes 自动创建，有 ownerReference 的 ConfigMap 由控制器管理
+		if isExcluded(c.Namespace) || c.Name == "kube-root-ca.crt" || len(c.OwnerReferences) > 0 || configMaps[c.Namespace+"/"+c.Name] {
+			continue
+		}
+		unusedConfigMaps[c.Namespace] = append(unusedConfigMaps[c.Namespace], c.Name)
+	}
+
+	secretList, err := client.Clientset.CoreV1().Secrets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	unusedSecrets := make(map[string][]string)
+	for _, s := range secretList.Items {
+		if isExcluded(s.Namespace) || len(s.OwnerReferences) > 0 || secrets[s.Namespace+"/"+s.Name] {
+			continue
+		}
+		if s.Type == corev1.SecretTypeServiceAccountToken || s.Type == helmReleaseSecretType {
+			continue
+		}
+		unusedSecrets[s.Namespace] = append(unusedSecrets[s.Namespace], s.Name)
+	}
+
+	for _, n := range getSortedKeys(unusedConfigMaps) {
+		if data, ok := namespaceData[n]; ok {
+			data.UnusedConfigMapCount = len(unusedConfigMaps[n])
+		}
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下存在未被引用的 ConfigMap", n), fmt.Sprintf("ConfigMap: %s", strings.Join(unusedConfigMaps[n], ", ")), 1))
+	}
+
+	for _, n := range getSortedKeys(unusedSecrets) {
+		if data, ok := namespaceData[n]; ok {
+			data.UnusedSecretCount = len(unusedSecrets[n])
+		}
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下存在未被引用的 Secret", n), fmt.Sprintf("Secret: %s", strings.Join(unusedSecrets[n], ", ")), 1))
+	}
+
+	pvList, err := client.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Released 状态的 PV 汇总为一条可回收容量的巡检结果，单个 PV 的告警由存储巡检给出
+	var releasedVolumes []string
+	releasedCapacity := resource.Quantity{}
+	for _, pv := range pvList.Items {
+		if pv.Status.Phase != corev1.VolumeReleased {
+			continue
+		}
+
+		releasedCapacity.Add(*pv.Spec.Capacity.Storage())
+		volume := fmt.Sprintf("%s (%s)", pv.Name, pv.Spec.Capacity.Storage().String())
+		if pv.Spec.ClaimRef != nil {
+			volume = fmt.Sprintf("%s (%s，原 PVC 为 %s/%s)", pv.Name, pv.Spec.Capacity.Storage().String(), pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+		}
+		releasedVolumes = append(releasedVolumes, volume)
+	}
+
+	if len(releasedVolumes) > 0 {
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Released 状态的 PV 可回收 %s 存储容量", releasedCapacity.String()), fmt.Sprintf("%d 个 PV 原绑定的 PVC 已删除，存储卷未被回收: %s", len(releasedVolumes), strings.Join(releasedVolumes, ", ")), 1))
+	}
+
+	jobList, err := client.Clientset.BatchV1().Jobs("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	completedJobs := make(map[string][]string)
+	for _, j := range jobList.Items {
+		// CronJob 创建的 Job 由 successfulJobsHistoryLimit 清理
+		if isExcluded(j.Namespace) || j.Spec.TTLSecondsAfterFinished != nil || !isJobFinished(&j) {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&j); owner != nil && owner.Kind == "CronJob" {
+			continue
+		}
+		completedJobs[j.Namespace] = append(completedJobs[j.Namespace], j.Name)
+	}
+
+	for _, n := range getSortedKeys(completedJobs) {
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下已完成的 Job 没有设置 TTL", n), fmt.Sprintf("未设置 ttlSecondsAfterFinished 的 Job: %s", strings.Join(completedJobs[n], ", ")), 1))
+	}
+
+	serviceList, err := client.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	endpointSliceList, err := client.Clientset.DiscoveryV1().EndpointSlices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	endpointSlices := make(map[string][]discoveryv1.EndpointSlice)
+	for _, e := range endpointSliceList.Items {
+		key := e.Namespace + "/" + e.Labels[discoveryv1.LabelServiceName]
+		endpointSlices[key] = append(endpointSlices[key], e)
+	}
+
+	for _, s := range serviceList.Items {
+		if isExcluded(s.Namespace) || s.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+
+		days := int(time.Since(s.CreationTimestamp.Time).Hours() / 24)
+		if days < emptyServiceDays || getReadyEndpointCount(endpointSlices[s.Namespace+"/"+s.Name]) > 0 {
+			continue
+		}
+
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 长期没有 Endpoint", s.Namespace, s.Name), fmt.Sprintf("Service %s 已创建 %d 天，没有就绪的 Endpoint", s.Name, days), 1))
+	}
+
+	return resourceInspections, nil
+}
+
+// getReferencedConfigs 返回被 Pod、工作负载模板、ServiceAccount 以及 Ingress 引用的 ConfigMap 和 Secret，键为 namespace/name
+func getReferencedConfigs(ctx context.Context, client *apis.Client) (map[string]bool, map[string]bool, error) {
+	configMaps := make(map[string]bool)
+	secrets := make(map[string]bool)
+
+	var podSpecs []*corev1.PodSpec
+	var namespaces []string
+	addPodSpec := func(namespace string, spec *corev1.PodSpec) {
+		namespaces = append(namespaces, namespace)
+		podSpecs = append(podSpecs, spec)
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range podList.Items {
+		addPodSpec(podList.Items[i].Namespace, &podList.Items[i].Spec)
+	}
+
+	deploymentList, err := client.Clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range deploymentList.Items {
+		addPodSpec(deploymentList.Items[i].Namespace, &deploymentList.Items[i].Spec.Template.Spec)
+	}
+
+	statefulSetList, err := client.Clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range statefulSetList.Items {
+		addPodSpec(statefulSetList.Items[i].Namespace, &statefulSetList.Items[i].Spec.Template.Spec)
+	}
+
+	daemonSetList, err := client.Clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range daemonSetList.Items {
+		addPodSpec(daemonSetList.Items[i].Namespace, &daemonSetList.Items[i].Spec.Template.Spec)
+	}
+
+	cronJobList, err := client.Clientset.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range cronJobList.Items {
+		addPodSpec(cronJobList.Items[i].Namespace, &cronJobList.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+	}
+
+	for i, spec := range podSpecs {
+		namespace := namespaces[i]
+		for _, s := range spec.ImagePullSecrets {
+			secrets[namespace+"/"+s.Name] = true
+		}
+
+		for _, v := range spec.Volumes {
+			if v.ConfigMap != nil {
+				configMaps[namespace+"/"+v.ConfigMap.Name] = true
+			}
+			if v.Secret != nil {
+				secrets[namespace+"/"+v.Secret.SecretName] = true
+			}
+			if v.Projected != nil {
+				for _, source := range v.Projected.Sources {
+					if source.ConfigMap != nil {
+						configMaps[namespace+"/"+source.ConfigMap.Name] = true
+					}
+					if source.Secret != nil {
+						secrets[namespace+"/"+source.Secret.Name] = true
+					}
+				}
+			}
+		}
+
+		var containers []corev1.Container
+		containers = append(containers, spec.InitContainers...)
+		containers = append(containers, spec.Containers...)
+		for _, c := range containers {
+			for _, e := range c.EnvFrom {
+				if e.ConfigMapRef != nil {
+					configMaps[namespace+"/"+e.ConfigMapRef.Name] = true
+				}
+				if e.SecretRef != nil {
+					secrets[namespace+"/"+e.SecretRef.Name] = true
+				}
+			}
+
+			for _, e := range c.Env {
+				if e.ValueFrom == nil {
+					continue
+				}
+				if e.ValueFrom.ConfigMapKeyRef != nil {
+					configMaps[namespace+"/"+e.ValueFrom.ConfigMapKeyRef.Name] = true
+				}
+				if e.ValueFrom.SecretKeyRef != nil {
+					secrets[namespace+"/"+e.ValueFrom.SecretKeyRef.Name] = true
+				}
+			}
+		}
+	}
+
+	serviceAccountList, err := client.Clientset.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sa := range serviceAccountList.Items {
+		for _, s := range sa.Secrets {
+			secrets[sa.Namespace+"/"+s.Name] = true
+		}
+		for _, s := range sa.ImagePullSecrets {
+			secrets[sa.Namespace+"/"+s.Name] = true
+		}
+	}
+
+	ingressList, err := client.Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, i := range ingressList.Items {
+		for _, tls := range i.Spec.TLS {
+			secrets[i.Namespace+"/"+tls.SecretName] = true
+		}
+	}
+
+	return configMaps, secrets, nil
+}
+
+// isJobFinished Job 存在状态为 True 的 Complete 或 Failed 条件
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getSortedKeys(m map[string][]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+type controlPlaneChecker struct{}
+
+func (c *controlPlaneChecker) Name() string { return "control_plane" }
+
+func (c *controlPlaneChecker) Category() Category { return CategoryCore }
+
+func (c *controlPlaneChecker) Enabled(config *apis.KubernetesConfig) bool {
+	coreConfig := config.ClusterCoreConfig
+	return coreConfig != nil && (coreConfig.APIServerHealthCheck || coreConfig.EtcdHealthCheck || coreConfig.SchedulerHealthCheck || coreConfig.ControllerManagerHealthCheck)
+}
+
+func (c *controlPlaneChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterCoreConfig
+}
+
+func (c *controlPlaneChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetControlPlane(ctx, client, config.ClusterCoreConfig)
+}
+
+func GetControlPlane(ctx context.Context, client *apis.Client, coreConfig *apis.ClusterCoreConfig) ([]*apis.Inspection, error) {
+	coreInspections := apis.NewInspections()
+
+	if coreConfig.APIServerHealthCheck || coreConfig.EtcdHealthCheck {
+		for _, path := range []string{"/readyz", "/livez"} {
+			body, err := client.Clientset.Discovery().RESTClient().Get().AbsPath(path).Param("verbose", "true").DoRaw(ctx)
+			if err != nil && len(body) == 0 {
+				if coreConfig.APIServerHealthCheck {
+					coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("API Server %s 检查失败", path), err.Error(), 3))
+				}
+				continue
+			}
+
+			for _, check := range getFailedHealthChecks(string(body)) {
+				if strings.HasPrefix(check, "etcd") {
+					if !coreConfig.EtcdHealthCheck {
+						continue
+					}
+					coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("ETCD %s 检查失败", path), fmt.Sprintf("%s 子检查 %s", path, check), 3))
+				} else if coreConfig.APIServerHealthCheck {
+					coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("API Server %s 检查失败", path), fmt.Sprintf("%s 子检查 %s", path, check), 2))
+				}
+			}
+		}
+	}
+
+	// 控制面组件以静态 Pod 运行时才能检查，RKE 等以容器方式运行的集群没有对应 Pod
+	podList, err := client.Clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range []struct {
+		name   string
+		enable bool
+	}{
+		{"kube-scheduler", coreConfig.SchedulerHealthCheck},
+		{"kube-controller-manager", coreConfig.ControllerManagerHealthCheck},
+		{"etcd", coreConfig.EtcdHealthCheck},
+	} {
+		if !component.enable {
+			continue
+		}
+
+		for _, pod := range podList.Items {
+			if !isControlPlanePod(&pod, component.name) || pod.Status.Phase == corev1.PodSucceeded {
+				continue
+			}
+
+			if !isPodReady(&pod) {
+				coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("%s 组件异常", component.name), fmt.Sprintf("Node %s 上的 Pod %s 未就绪，当前状态 %s", pod.Spec.NodeName, pod.Name, pod.Status.Phase), 3))
+			}
+		}
+	}
+
+	return coreInspections, nil
+}
+
+// getFailedHealthChecks 解析 /readyz?verbose 与 /livez?verbose 的输出，返回失败的子检查
+func getFailedHealthChecks(body string) []string {
+	var failed []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[-]") {
+			failed = append(failed, strings.TrimPrefix(line, "[-]"))
+		}
+	}
+	return failed
+}
+
+// isControlPlanePod 只匹配 kubeadm 等方式创建的控制面静态 Pod
+func isControlPlanePod(pod *corev1.Pod, component string) bool {
+	if pod.Labels["component"] != component {
+		return false
+	}
+
+	_, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]
+	return mirror || pod.Labels["tier"] == "control-plane"
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
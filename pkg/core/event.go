@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/strings/slices"
+	"sort"
+	"time"
+)
+
+var (
+	defaultEventLookbackHours = 24
+	// 同一对象同一原因的 Warning 事件次数达到该值时提升为等级 2
+	eventFrequentCount = int32(10)
+)
+
+type eventChecker struct{}
+
+func (c *eventChecker) Name() string { return "event" }
+
+func (c *eventChecker) Category() Category { return CategoryResource }
+
+func (c *eventChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.EventConfig != nil && config.ClusterResourceConfig.EventConfig.Enable
+}
+
+func (c *eventChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.EventConfig
+}
+
+func (c *eventChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetWarningEvents(ctx, client, config.ClusterResourceConfig.EventConfig)
+}
+
+type eventGroup struct {
+	namespace string
+	kind      string
+	name      string
+	reason    string
+	message   string
+	count     int32
+	lastTime  time.Time
+}
+
+func GetWarningEvents(ctx context.Context, client *apis.Client, eventConfig *apis.EventConfig) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	lookbackHours := eventConfig.LookbackHours
+	if lookbackHours <= 0 {
+		lookbackHours = defaultEventLookbackHours
+	}
+	since := time.Now().Add(-time.Duration(lookbackHours) * time.Hour)
+
+	selector := fields.OneTermEqualSelector("type", corev1.EventTypeWarning)
+	eventList, err := client.Clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*eventGroup)
+	for _, e := range eventList.Items {
+		if !isNamespaceIncluded(e.InvolvedObject.Namespace, eventConfig.IncludeNamespaces, eventConfig.ExcludeNamespaces) {
+			continue
+		}
+
+		lastTime := getEventTime(&e)
+		if lastTime.Before(since) {
+			continue
+		}
+
+		count := e.Count
+		if e.Series != nil && e.Series.Count > count {
+			count = e.Series.Count
+		}
+		if count <= 0 {
+			count = 1
+		}
+
+		key := fmt.Sprintf("%s/%s/%s/%s", e.InvolvedObject.Namespace, e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Reason)
+		group, ok := groups[key]
+		if !ok {
+			group = &eventGroup{
+				namespace: e.InvolvedObject.Namespace,
+				kind:      e.InvolvedObject.Kind,
+				name:      e.InvolvedObject.Name,
+				reason:    e.Reason,
+			}
+			groups[key] = group
+		}
+
+		group.count += count
+		if !lastTime.Before(group.lastTime) {
+			group.lastTime = lastTime
+			group.message = e.Message
+		}
+	}
+
+	var eventGroups []*eventGroup
+	for _, g := range groups {
+		eventGroups = append(eventGroups, g)
+	}
+
+	sort.Slice(eventGroups, func(i, j int) bool {
+		return eventGroups[i].count > eventGroups[j].count
+	})
+
+	for _, g := range eventGroups {
+		level := 1
+		if g.count >= eventFrequentCount {
+			level = 2
+		}
+
+		title := fmt.Sprintf("%s %s 出现 %d 次 %s 事件", g.kind, g.name, g.count, g.reason)
+		if g.namespace != "" {
+			title = fmt.Sprintf("命名空间 %s 下 %s", g.namespace, title)
+		}
+
+		resourceInspections = append(resourceInspections, apis.NewInspection(title, fmt.Sprintf("最近一次事件时间 %s: %s", g.lastTime.Format(time.DateTime), g.message), level))
+	}
+
+	return resourceInspections, nil
+}
+
+func getEventTime(e *corev1.Event) time.Time {
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// isNamespaceIncluded include 为空时包含所有命名空间，exclude 优先于 include
+func isNamespaceIncluded(namespace string, include, exclude []string) bool {
+	if slices.Contains(exclude, namespace) {
+		return false
+	}
+
+	return len(include) == 0 || slices.Contains(include, namespace)
+}
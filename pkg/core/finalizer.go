@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+	"time"
+)
+
+var (
+	defaultFinalizerThresholdMinutes = 30
+	customResourceDefinitionResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+)
+
+type finalizerChecker struct{}
+
+func (c *finalizerChecker) Name() string { return "finalizer" }
+
+func (c *finalizerChecker) Category() Category { return CategoryResource }
+
+func (c *finalizerChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.FinalizerConfig != nil && config.ClusterResourceConfig.FinalizerConfig.Enable
+}
+
+func (c *finalizerChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.FinalizerConfig
+}
+
+func (c *finalizerChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetTerminatingResources(ctx, client, config.ClusterResourceConfig.FinalizerConfig)
+}
+
+// GetTerminatingResources 检查长时间处于删除中的命名空间、Pod、PVC 以及自定义资源，并列出阻塞删除的 finalizer
+func GetTerminatingResources(ctx context.Context, client *apis.Client, finalizerConfig *apis.FinalizerConfig) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	thresholdMinutes := finalizerConfig.ThresholdMinutes
+	if thresholdMinutes <= 0 {
+		thresholdMinutes = defaultFinalizerThresholdMinutes
+	}
+	threshold := time.Duration(thresholdMinutes) * time.Minute
+
+	isStuck := func(deletionTimestamp *metav1.Time) bool {
+		return deletionTimestamp != nil && time.Since(deletionTimestamp.Time) > threshold
+	}
+
+	namespaceList, err := client.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range namespaceList.Items {
+		if n.Status.Phase != corev1.NamespaceTerminating || !isStuck(n.DeletionTimestamp) {
+			continue
+		}
+
+		var finalizers []string
+		finalizers = append(finalizers, n.Finalizers...)
+		for _, f := range n.Spec.Finalizers {
+			finalizers = append(finalizers, string(f))
+		}
+
+		message := fmt.Sprintf("命名空间 %s 已删除 %s，finalizer: %s", n.Name, getTerminatingDuration(n.DeletionTimestamp), strings.Join(finalizers, ", "))
+		for _, c := range n.Status.Conditions {
+			if c.Status == corev1.ConditionTrue && (c.Type == corev1.NamespaceContentRemaining || c.Type == corev1.NamespaceFinalizersRemaining || c.Type == corev1.NamespaceDeletionContentFailure) {
+				message += "; " + c.Message
+			}
+		}
+
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 长时间处于 Terminating 状态", n.Name), message, 2))
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range podList.Items {
+		if isStuck(p.DeletionTimestamp) {
+			resourceInspections = append(resourceInspections, getTerminatingInspection("Pod", p.Namespace, p.Name, p.DeletionTimestamp, p.Finalizers))
+		}
+	}
+
+	pvcList, err := client.Clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pvcList.Items {
+		if isStuck(p.DeletionTimestamp) {
+			resourceInspections = append(resourceInspections, getTerminatingInspection("PVC", p.Namespace, p.Name, p.DeletionTimestamp, p.Finalizers))
+		}
+	}
+
+	crdList, err := client.DynamicClient.Resource(customResourceDefinitionResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logrus.Warnf("Failed to list CustomResourceDefinitions, skip custom resources: %v\n", err)
+		return resourceInspections, nil
+	}
+
+	for _, crd := range crdList.Items {
+		resource, kind := getCustomResource(&crd)
+		if resource.Version == "" {
+			continue
+		}
+
+		list, err := client.DynamicClient.Resource(resource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			logrus.Warnf("Failed to list custom resource %s: %v\n", resource.String(), err)
+			continue
+		}
+
+		for _, item := range list.Items {
+			if isStuck(item.GetDeletionTimestamp()) {
+				resourceInspections = append(resourceInspections, getTerminatingInspection(kind, item.GetNamespace(), item.GetName(), item.GetDeletionTimestamp(), item.GetFinalizers()))
+			}
+		}
+	}
+
+	return resourceInspections, nil
+}
+
+// getCustomResource 返回 CRD 存储版本对应的资源
+func getCustomResource(crd *unstructured.Unstructured) (schema.GroupVersionResource, string) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+
+	resource := schema.GroupVersionResource{Group: group, Resource: plural}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := version["storage"].(bool); storage {
+			resource.Version, _ = version["name"].(string)
+		}
+	}
+
+	return resource, kind
+}
+
+func getTerminatingInspection(kind, namespace, name string, deletionTimestamp *metav1.Time, finalizers []string) *apis.Inspection {
+	title := fmt.Sprintf("%s %s 长时间处于删除中", kind, name)
+	if namespace != "" {
+		title = fmt.Sprintf("命名空间 %s 下 %s %s 长时间处于删除中", namespace, kind, name)
+	}
+
+	message := fmt.Sprintf("%s %s 已删除 %s", kind, name, getTerminatingDuration(deletionTimestamp))
+	if len(finalizers) > 0 {
+		message += fmt.Sprintf("，被 finalizer 阻塞: %s", strings.Join(finalizers, ", "))
+	}
+
+	return apis.NewInspection(title, message, 1)
+}
+
+func getTerminatingDuration(deletionTimestamp *metav1.Time) string {
+	return time.Since(deletionTimestamp.Time).Truncate(time.Minute).String()
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"inspection-server/pkg/apis"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sort"
+	"strconv"
+)
+
+var defaultHelmMaxHistory = 10
+
+type helmChecker struct{}
+
+func (c *helmChecker) Name() string { return "helm" }
+
+func (c *helmChecker) Category() Category { return CategoryResource }
+
+func (c *helmChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.HelmConfig != nil && config.ClusterResourceConfig.HelmConfig.Enable
+}
+
+func (c *helmChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.HelmConfig
+}
+
+func (c *helmChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	releases, inspections, err := GetHelmReleases(ctx, client, config.ClusterResourceConfig.HelmConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.HelmRelease = releases
+	return inspections, nil
+}
+
+// helmRelease helm.sh/release.v1 Secret 中 release 字段的部分内容
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status      string `json:"status"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// GetHelmReleases 解析 Helm release Secret，检查每个 release 最新版本的状态以及保留的历史版本数量
+func GetHelmReleases(ctx context.Context, client *apis.Client, helmConfig *apis.HelmConfig) ([]*apis.HelmRelease, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	releases := apis.NewHelmReleases()
+
+	maxHistory := helmConfig.MaxHistory
+	if maxHistory <= 0 {
+		maxHistory = defaultHelmMaxHistory
+	}
+
+	secretList, err := client.Clientset.CoreV1().Secrets("").List(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("type", string(helmReleaseSecretType)).String()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 同一 release 只保留版本号最大的 Secret
+	latest := make(map[string]*corev1.Secret)
+	superseded := make(map[string]int)
+	for i := range secretList.Items {
+		s := &secretList.Items[i]
+		key := s.Namespace + "/" + s.Labels["name"]
+		if s.Labels["status"] == "superseded" {
+			superseded[key]++
+		}
+
+		version, _ := strconv.Atoi(s.Labels["version"])
+		if l, ok := latest[key]; ok {
+			if latestVersion, _ := strconv.Atoi(l.Labels["version"]); latestVersion >= version {
+				continue
+			}
+		}
+		latest[key] = s
+	}
+
+	for key, s := range latest {
+		release, err := decodeHelmRelease(s.Data["release"])
+		if err != nil {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Helm release Secret %s 解析失败", s.Namespace, s.Name), err.Error(), 1))
+			continue
+		}
+
+		releases = append(releases, &apis.HelmRelease{
+			Name:            release.Name,
+			Namespace:       release.Namespace,
+			Revision:        release.Version,
+			Status:          release.Info.Status,
+			Chart:           release.Chart.Metadata.Name,
+			ChartVersion:    release.Chart.Metadata.Version,
+			AppVersion:      release.Chart.Metadata.AppVersion,
+			SupersededCount: superseded[key],
+		})
+
+		title := fmt.Sprintf("命名空间 %s 下 Helm release %s 处于 %s 状态", release.Namespace, release.Name, release.Info.Status)
+		switch release.Info.Status {
+		case "failed":
+			resourceInspections = append(resourceInspections, apis.NewInspection(title, fmt.Sprintf("Chart %s-%s 第 %d 次部署失败: %s", release.Chart.Metadata.Name, release.Chart.Metadata.Version, release.Version, release.Info.Description), 2))
+		case "pending-install", "pending-upgrade", "pending-rollback":
+			resourceInspections = append(resourceInspections, apis.NewInspection(title, fmt.Sprintf("Chart %s-%s 第 %d 次部署未完成，release 被锁定时无法再次升级: %s", release.Chart.Metadata.Name, release.Chart.Metadata.Version, release.Version, release.Info.Description), 1))
+		}
+
+		if superseded[key] > maxHistory {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Helm release %s 保留了过多的历史版本", release.Namespace, release.Name), fmt.Sprintf("superseded 版本有 %d 个，超过 %d 个，升级时可设置 --history-max 清理", superseded[key], maxHistory), 1))
+		}
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+
+	return releases, resourceInspections, nil
+}
+
+// decodeHelmRelease release 字段为 base64 编码的 gzip 压缩 JSON
+func decodeHelmRelease(data []byte) (*helmRelease, error) {
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(b, []byte{0x1f, 0x8b, 0x08}) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		b, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	release := &helmRelease{}
+	if err := json.Unmarshal(b, release); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+	"sort"
+	"strings"
+)
+
+type imageChecker struct{}
+
+func (c *imageChecker) Name() string { return "image" }
+
+func (c *imageChecker) Category() Category { return CategoryResource }
+
+func (c *imageChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.ImageConfig != nil && config.ClusterResourceConfig.ImageConfig.Enable
+}
+
+func (c *imageChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.ImageConfig
+}
+
+func (c *imageChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	images, inspections, err := GetImages(ctx, client, config.ClusterResourceConfig.ImageConfig, config.ClusterResourceConfig.WorkloadConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Image = images
+	return inspections, nil
+}
+
+// GetImages 检查运行中 Pod 的镜像，模板中配置的工作负载视为关键工作负载
+func GetImages(ctx context.Context, client *apis.Client, imageConfig *apis.ImageConfig, workloadConfig *apis.WorkloadConfig) ([]*apis.Image, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	images := apis.NewImages()
+
+	criticalWorkloads := getCriticalWorkloads(workloadConfig)
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Running"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workloadOwners, err := getWorkloadOwners(ctx, client, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imageMap := make(map[string]*apis.Image)
+	workloads := make(map[string]bool)
+	// workload/container 对应的镜像 digest
+	workloadImageIDs := make(map[string]map[string]bool)
+	var workloadContainers []string
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		kind, name := getPodWorkload(pod, workloadOwners)
+		workloadKey := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+
+		for _, cs := range pod.Status.ContainerStatuses {
+			image, ok := imageMap[cs.Image]
+			if !ok {
+				image = &apis.Image{
+					Name:     cs.Image,
+					ImageIDs: []string{},
+				}
+				imageMap[cs.Image] = image
+			}
+			image.PodCount++
+
+			imageID := getImageDigest(cs.ImageID)
+			if imageID == "" {
+				continue
+			}
+
+			if !slices.Contains(image.ImageIDs, imageID) {
+				image.ImageIDs = append(image.ImageIDs, imageID)
+			}
+
+			containerKey := fmt.Sprintf("%s/%s", workloadKey, cs.Name)
+			if _, ok := workloadImageIDs[containerKey]; !ok {
+				workloadImageIDs[containerKey] = make(map[string]bool)
+				workloadContainers = append(workloadContainers, containerKey)
+			}
+			workloadImageIDs[containerKey][imageID] = true
+		}
+
+		if workloads[workloadKey] {
+			continue
+		}
+		workloads[workloadKey] = true
+
+		var issues []string
+		level := 0
+		for _, c := range pod.Spec.Containers {
+			if !hasImageTag(c.Image) {
+				issues = append(issues, fmt.Sprintf("容器 %s 镜像 %s 使用 latest 标签或未指定标签", c.Name, c.Image))
+				level = max(level, 1)
+			}
+
+			if c.ImagePullPolicy == corev1.PullAlways && criticalWorkloads[workloadKey] {
+				issues = append(issues, fmt.Sprintf("关键工作负载容器 %s 的 imagePullPolicy 为 Always，镜像仓库不可用时将无法重启", c.Name))
+				level = max(level, 1)
+			}
+
+			if len(imageConfig.AllowRegistries) > 0 && !isImageRegistryAllowed(c.Image, imageConfig.AllowRegistries) {
+				issues = append(issues, fmt.Sprintf("容器 %s 镜像 %s 不在允许的镜像仓库中", c.Name, c.Image))
+				level = max(level, 2)
+			}
+		}
+
+		if len(issues) > 0 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 镜像不规范", pod.Namespace, kind, name), strings.Join(issues, "; "), level))
+		}
+	}
+
+	for _, containerKey := range workloadContainers {
+		if len(workloadImageIDs[containerKey]) > 1 {
+			parts := strings.Split(containerKey, "/")
+			var imageIDs []string
+			for imageID := range workloadImageIDs[containerKey] {
+				imageIDs = append(imageIDs, imageID)
+			}
+			sort.Strings(imageIDs)
+
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 的 Pod 运行了不同版本的镜像", parts[0], parts[1], parts[2]), fmt.Sprintf("容器 %s 运行的镜像 digest: %s", parts[3], strings.Join(imageIDs, ", ")), 1))
+		}
+	}
+
+	for _, image := range imageMap {
+		images = append(images, image)
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Name < images[j].Name
+	})
+
+	return images, resourceInspections, nil
+}
+
+func getCriticalWorkloads(workloadConfig *apis.WorkloadConfig) map[string]bool {
+	criticalWorkloads := make(map[string]bool)
+	if workloadConfig == nil {
+		return criticalWorkloads
+	}
+
+	for kind, configs := range map[string][]*apis.WorkloadDetailConfig{
+		"Deployment":  workloadConfig.Deployment,
+		"StatefulSet": workloadConfig.Statefulset,
+		"DaemonSet":   workloadConfig.Daemonset,
+		"Job":         workloadConfig.Job,
+		"CronJob":     workloadConfig.Cronjob,
+	} {
+		for _, c := range configs {
+			criticalWorkloads[fmt.Sprintf("%s/%s/%s", c.Namespace, kind, c.Name)] = true
+		}
+	}
+
+	return criticalWorkloads
+}
+
+func hasImageTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+
+	name := image[strings.LastIndex(image, "/")+1:]
+	index := strings.LastIndex(name, ":")
+	if index == -1 {
+		return false
+	}
+
+	return name[index+1:] != "latest"
+}
+
+// getImageRegistry 返回镜像所在仓库，没有指定仓库的镜像属于 docker.io
+func getImageRegistry(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0]
+	}
+
+	return "docker.io"
+}
+
+func isImageRegistryAllowed(image string, allowRegistries []string) bool {
+	registry := getImageRegistry(image)
+	fullName := image
+	if !strings.HasPrefix(image, registry+"/") {
+		fullName = registry + "/" + image
+	}
+
+	for _, allow := range allowRegistries {
+		allow = strings.TrimSuffix(allow, "/")
+		if registry == allow || strings.HasPrefix(fullName, allow+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getImageDigest(imageID string) string {
+	if index := strings.LastIndex(imageID, "@"); index != -1 {
+		return imageID[index+1:]
+	}
+
+	return strings.TrimPrefix(imageID, "docker://")
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"inspection-server/pkg/apis"
 	"inspection-server/pkg/common"
@@ -48,57 +49,22 @@ func Inspection(task *apis.Task) (error, strings.Builder) {
 			if k.ClusterID == clusterID && k.Enable {
 				sendMessageDetail = append(sendMessageDetail, fmt.Sprintf("集群 %s 巡检警告：", k.ClusterName))
 
-				clusterCore := apis.NewClusterCore()
-				clusterNode := apis.NewClusterNode()
-				clusterResource := apis.NewClusterResource()
-				coreInspections := apis.NewInspections()
-				nodeInspections := apis.NewInspections()
-				resourceInspections := apis.NewInspections()
-
-				NodeNodeArray, nodeInspectionArray, err := GetNodes(client, k.ClusterNodeConfig.NodeConfig)
-				if err != nil {
-					errMessage.WriteString(fmt.Sprintf("获取集群 %s 节点相关巡检信息时失败: %v\n", clusterID, err))
-				}
-				nodeInspections = append(nodeInspections, nodeInspectionArray...)
-
-				ResourceWorkloadArray, resourceInspectionArray, err := GetWorkloads(client, k.ClusterResourceConfig.WorkloadConfig)
-				if err != nil {
-					errMessage.WriteString(fmt.Sprintf("获取集群 %s 工作负载相关巡检信息时失败: %v\n", clusterID, err))
-				}
-				resourceInspections = append(resourceInspections, resourceInspectionArray...)
-
-				if k.ClusterResourceConfig.NamespaceConfig.Enable {
-					ResourceNamespaceArray, resourceInspectionArray, err := GetNamespaces(client)
-					if err != nil {
-						errMessage.WriteString(fmt.Sprintf("获取集群 %s 命名空间相关巡检信息时失败: %v\n", clusterID, err))
-					}
-
-					clusterResource.Namespace = ResourceNamespaceArray
-					resourceInspections = append(resourceInspections, resourceInspectionArray...)
-				}
-
-				if k.ClusterResourceConfig.ServiceConfig.Enable {
-					ResourceServiceArray, resourceInspectionArray, err := GetServices(client)
-					if err != nil {
-						errMessage.WriteString(fmt.Sprintf("获取集群 %s Service 相关巡检信息时失败: %v\n", clusterID, err))
-					}
-
-					clusterResource.Service = ResourceServiceArray
-					resourceInspections = append(resourceInspections, resourceInspectionArray...)
+				kubernetesData := &apis.Kubernetes{
+					ClusterID:       k.ClusterID,
+					ClusterName:     k.ClusterName,
+					ClusterCore:     apis.NewClusterCore(),
+					ClusterNode:     apis.NewClusterNode(),
+					ClusterResource: apis.NewClusterResource(),
 				}
 
-				if k.ClusterResourceConfig.IngressConfig.Enable {
-					ResourceIngressArray, resourceInspectionArray, err := GetIngress(client)
-					if err != nil {
-						errMessage.WriteString(fmt.Sprintf("获取集群 %s Ingress 相关巡检信息时失败: %v\n", clusterID, err))
-					}
+				errMessage.WriteString(RunCheckers(context.TODO(), client, k, kubernetesData))
 
-					clusterResource.Ingress = ResourceIngressArray
-					resourceInspections = append(resourceInspections, resourceInspectionArray...)
-				}
-
-				clusterNode.Nodes = NodeNodeArray
-				clusterResource.Workloads = ResourceWorkloadArray
+				clusterCore := kubernetesData.ClusterCore
+				clusterNode := kubernetesData.ClusterNode
+				clusterResource := kubernetesData.ClusterResource
+				coreInspections := clusterCore.Inspections
+				nodeInspections := clusterNode.Inspections
+				resourceInspections := clusterResource.Inspections
 
 				if allGrafanaInspections[k.ClusterName] != nil {
 					if len(allGrafanaInspections[k.ClusterName].ClusterCoreInspection) > 0 {
@@ -143,13 +109,7 @@ func Inspection(task *apis.Task) (error, strings.Builder) {
 					}
 				}
 
-				kubernetes = append(kubernetes, &apis.Kubernetes{
-					ClusterID:       k.ClusterID,
-					ClusterName:     k.ClusterName,
-					ClusterCore:     clusterCore,
-					ClusterNode:     clusterNode,
-					ClusterResource: clusterResource,
-				})
+				kubernetes = append(kubernetes, kubernetesData)
 			}
 		}
 	}
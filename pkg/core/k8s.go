@@ -4,51 +4,109 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
 	"inspection-server/pkg/apis"
 	"inspection-server/pkg/common"
 	"io"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/utils/strings/slices"
-	"log"
+	"net"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
 	warning = "warning"
 	success = "success"
+	running = "running"
+	failed  = "failed"
+	// 滚动更新超过该时间没有进展时视为卡住
+	stuckRolloutMinutes = 10
 )
 
 func GetNodes(client *apis.Client, nodesConfig []*apis.NodeConfig) ([]*apis.Node, []*apis.Inspection, error) {
 	nodeNodeArray := apis.NewNodes()
 	nodeInspections := apis.NewInspections()
 
+	nodeList, err := client.Clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodes := make(map[string]*corev1.Node)
+	for i := range nodeList.Items {
+		nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+	}
+
 	set := labels.Set(map[string]string{"name": "inspection-agent"})
 	podList, err := client.Clientset.CoreV1().Pods(common.InspectionNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: set.String()})
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// 非 Rancher 管理的集群节点上没有 pod-limits、pod-requests 注解，需要根据节点上的 Pod 计算
+	nodePodList, err := client.Clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodePods := make(map[string][]*corev1.Pod)
+	for i := range nodePodList.Items {
+		p := &nodePodList.Items[i]
+		if p.Spec.NodeName != "" {
+			nodePods[p.Spec.NodeName] = append(nodePods[p.Spec.NodeName], p)
+		}
+	}
+
+	nodeMetrics, err := getNodeMetrics(context.TODO(), client)
+	if err != nil {
+		logrus.Warnf("Failed to get node metrics from metrics.k8s.io: %v\n", err)
+	}
+
+	podMetrics, err := getPodMetrics(context.TODO(), client, "")
+	if err != nil {
+		logrus.Warnf("Failed to get pod metrics from metrics.k8s.io: %v\n", err)
+	}
+
+	agentNodes := make(map[string]bool)
 	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		agentNodes[pod.Spec.NodeName] = true
+
 		for _, n := range nodesConfig {
 			if slices.Contains(n.Names, pod.Spec.NodeName) {
-				node, err := client.Clientset.CoreV1().Nodes().Get(context.TODO(), pod.Spec.NodeName, metav1.GetOptions{})
-				if err != nil {
-					return nil, nil, err
+				node, ok := nodes[pod.Spec.NodeName]
+				if !ok {
+					continue
 				}
 
 				podLimits := getResourceList(node.Annotations["management.cattle.io/pod-limits"])
 				podRequests := getResourceList(node.Annotations["management.cattle.io/pod-requests"])
+				if podLimits == nil || podRequests == nil {
+					podRequests, podLimits = getPodsResourceList(nodePods[node.Name])
+				}
+
+				usage := nodeMetrics[node.Name]
 
 				limitsCPU := podLimits.Cpu().Value()
 				limitsMemory := podLimits.Memory().Value()
@@ -59,33 +117,39 @@ func GetNodes(client *apis.Client, nodesConfig []*apis.NodeConfig) ([]*apis.Node
 				allocatableMemory, _ := node.Status.Allocatable.Memory().AsInt64()
 				allocatablePods, _ := node.Status.Allocatable.Pods().AsInt64()
 
-				//fmt.Println(podLimits)
-				//fmt.Println(limitsCPU)
-				//fmt.Println(limitsMemory)
-				//fmt.Println(requestsCPU)
-				//fmt.Println(requestsMemory)
-				//fmt.Println(requestsPods)
-				//fmt.Println("limitsCPU:")
-				//fmt.Println(float64(limitsCPU) / float64(allocatableCPU))
-				if float64(limitsCPU)/float64(allocatableCPU) > 0.8 {
-					//nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s limits CPU 超过 80%", pod.Spec.NodeName), fmt.Sprintf("limits CPU %d, allocatable CPU %d", limitsCPU, allocatableCPU), 2))
-					nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s limits CPU 超过百分之 80", pod.Spec.NodeName), fmt.Sprintf(""), 2))
-				}
-
-				if float64(limitsMemory)/float64(allocatableMemory) > 0.8 {
-					nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s limits Memory 超过百分之 80", pod.Spec.NodeName), fmt.Sprintf(""), 2))
-				}
-
-				if float64(requestsCPU)/float64(allocatableCPU) > 0.8 {
-					nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s requests CPU 超过百分之 80", pod.Spec.NodeName), fmt.Sprintf(""), 2))
+				thresholds := n.Thresholds
+				if thresholds == nil {
+					thresholds = apis.NewNodeThresholdConfig()
 				}
 
-				if float64(requestsMemory)/float64(allocatableMemory) > 0.8 {
-					nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s requests Memory 超过百分之 80", pod.Spec.NodeName), fmt.Sprintf(""), 2))
+				allocatableMilliCPU := float64(node.Status.Allocatable.Cpu().MilliValue())
+				for _, t := range []struct {
+					metric      string
+					used        float64
+					allocatable float64
+					threshold   *apis.ThresholdConfig
+				}{
+					{"limits CPU", float64(podLimits.Cpu().MilliValue()), allocatableMilliCPU, thresholds.LimitsCPU},
+					{"limits Memory", float64(limitsMemory), float64(allocatableMemory), thresholds.LimitsMemory},
+					{"requests CPU", float64(podRequests.Cpu().MilliValue()), allocatableMilliCPU, thresholds.RequestsCPU},
+					{"requests Memory", float64(requestsMemory), float64(allocatableMemory), thresholds.RequestsMemory},
+					{"requests Pods", float64(requestsPods), float64(allocatablePods), thresholds.RequestsPods},
+				} {
+					inspection := getThresholdInspection(pod.Spec.NodeName, t.metric, t.used, t.allocatable, t.threshold)
+					if inspection != nil {
+						nodeInspections = append(nodeInspections, inspection)
+					}
 				}
 
-				if float64(requestsPods)/float64(allocatablePods) > 0.8 {
-					nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s requests Pods 超过百分之 80", pod.Spec.NodeName), fmt.Sprintf(""), 2))
+				if usage != nil {
+					for _, inspection := range []*apis.Inspection{
+						getThresholdInspection(pod.Spec.NodeName, "usage CPU", float64(usage.Cpu().MilliValue()), allocatableMilliCPU, thresholds.UsageCPU),
+						getThresholdInspection(pod.Spec.NodeName, "usage Memory", float64(usage.Memory().Value()), float64(allocatableMemory), thresholds.UsageMemory),
+					} {
+						if inspection != nil {
+							nodeInspections = append(nodeInspections, inspection)
+						}
+					}
 				}
 
 				var commands []string
@@ -112,9 +176,21 @@ func GetNodes(client *apis.Client, nodesConfig []*apis.NodeConfig) ([]*apis.Node
 					}
 				}
 
+				var condition []apis.Condition
+				for _, c := range node.Status.Conditions {
+					condition = append(condition, apis.Condition{
+						Type:    string(c.Type),
+						Status:  string(c.Status),
+						Reason:  c.Reason,
+						Message: c.Message,
+					})
+				}
+
 				nodeData := &apis.Node{
-					Name:   pod.Spec.NodeName,
-					HostIP: pod.Status.HostIP,
+					Name:          pod.Spec.NodeName,
+					HostIP:        pod.Status.HostIP,
+					Unschedulable: node.Spec.Unschedulable,
+					Condition:     condition,
 					Resource: &apis.Resource{
 						LimitsCPU:         limitsCPU,
 						LimitsMemory:      limitsMemory,
@@ -124,7 +200,10 @@ func GetNodes(client *apis.Client, nodesConfig []*apis.NodeConfig) ([]*apis.Node
 						AllocatableCPU:    allocatableCPU,
 						AllocatableMemory: allocatableMemory,
 						AllocatablePods:   allocatablePods,
+						UsageMilliCPU:     usage.Cpu().MilliValue(),
+						UsageMemory:       usage.Memory().Value(),
 					},
+					TopPods: getTopPodUsages(nodePods[node.Name], podMetrics, topConsumerPodCount),
 					Commands: &apis.Command{
 						Stdout: results,
 						Stderr: stderr,
@@ -136,6 +215,14 @@ func GetNodes(client *apis.Client, nodesConfig []*apis.NodeConfig) ([]*apis.Node
 		}
 	}
 
+	for _, node := range nodeList.Items {
+		nodeInspections = append(nodeInspections, getNodeConditionInspections(&node)...)
+
+		if !agentNodes[node.Name] {
+			nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s 没有运行 inspection-agent", node.Name), fmt.Sprintf("Node %s 上没有处于 Running 状态的 inspection-agent Pod，无法执行节点命令巡检", node.Name), 1))
+		}
+	}
+
 	return nodeNodeArray, nodeInspections, nil
 }
 
@@ -200,10 +287,8 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 	ResourceWorkloadArray := apis.NewWorkload()
 	resourceInspections := apis.NewInspections()
 
-	deployState := warning
 	dsState := warning
 	stsState := warning
-	jState := warning
 
 	for _, deploy := range workloadConfig.Deployment {
 		deployment, err := client.Clientset.AppsV1().Deployments(deploy.Namespace).Get(context.TODO(), deploy.Name, metav1.GetOptions{})
@@ -214,19 +299,22 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 			return nil, nil, err
 		}
 
-		if isDeploymentAvailable(deployment) {
-			deployState = success
-		}
-
 		var condition []apis.Condition
 		for _, c := range deployment.Status.Conditions {
 			condition = append(condition, apis.Condition{
-				Type:   string(c.Type),
-				Status: string(c.Status),
-				Reason: c.Reason,
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
 			})
 		}
 
+		deployState := success
+		issues, level := getDeploymentRolloutIssues(deployment, condition)
+		if len(issues) > 0 {
+			deployState = warning
+		}
+
 		set := labels.Set(deployment.Spec.Selector.MatchLabels)
 		pods, err := GetPod(deploy.Regexp, deployment.Namespace, set, client.Clientset)
 		if err != nil {
@@ -245,7 +333,7 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 
 		ResourceWorkloadArray.Deployment = append(ResourceWorkloadArray.Deployment, deploymentData)
 		if deployState == warning {
-			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Deployment %s 警告", deploymentData.Name), fmt.Sprintf("命名空间 %s 下的 Deployment %s 处于非健康状态", deploymentData.Namespace, deploymentData.Name), 2))
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Deployment %s 警告", deploymentData.Name), fmt.Sprintf("命名空间 %s 下的 Deployment %s 滚动更新异常: %s", deploymentData.Namespace, deploymentData.Name, strings.Join(issues, "; ")), level))
 		}
 	}
 
@@ -265,9 +353,10 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 		var condition []apis.Condition
 		for _, c := range daemonSet.Status.Conditions {
 			condition = append(condition, apis.Condition{
-				Type:   string(c.Type),
-				Status: string(c.Status),
-				Reason: c.Reason,
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
 			})
 		}
 
@@ -309,9 +398,10 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 		var condition []apis.Condition
 		for _, c := range statefulset.Status.Conditions {
 			condition = append(condition, apis.Condition{
-				Type:   string(c.Type),
-				Status: string(c.Status),
-				Reason: c.Reason,
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
 			})
 		}
 
@@ -337,6 +427,11 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 		}
 	}
 
+	jobConfig := workloadConfig.JobConfig
+	if jobConfig == nil {
+		jobConfig = apis.NewJobConfig()
+	}
+
 	for _, j := range workloadConfig.Job {
 		job, err := client.Clientset.BatchV1().Jobs(j.Namespace).Get(context.TODO(), j.Name, metav1.GetOptions{})
 		if err != nil {
@@ -346,16 +441,15 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 			return nil, nil, err
 		}
 
-		if isJobCompleted(job) {
-			jState = success
-		}
+		jState, reason := getJobState(job, jobConfig.MaxRunningMinutes)
 
 		var condition []apis.Condition
 		for _, c := range job.Status.Conditions {
 			condition = append(condition, apis.Condition{
-				Type:   string(c.Type),
-				Status: string(c.Status),
-				Reason: c.Reason,
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
 			})
 		}
 
@@ -376,14 +470,196 @@ func GetWorkloads(client *apis.Client, workloadConfig *apis.WorkloadConfig) (*ap
 		}
 
 		ResourceWorkloadArray.Job = append(ResourceWorkloadArray.Job, jobData)
+		if jState != failed && jState != warning {
+			continue
+		}
+
+		failedPods, err := client.Clientset.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: set.String(), FieldSelector: "status.phase=Failed"})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		message := fmt.Sprintf("命名空间 %s 下的 Job %s %s", jobData.Namespace, jobData.Name, reason)
+		if len(failedPods.Items) > 0 {
+			message += fmt.Sprintf("; 失败的 Pod: %s", getJobFailedPodLogs(failedPods.Items, pods))
+		}
+
+		level := 2
 		if jState == warning {
-			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Job %s 警告", jobData.Name), fmt.Sprintf("命名空间 %s 下的 Job %s 处于非健康状态", jobData.Namespace, jobData.Name), 2))
+			level = 1
+		}
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Job %s 警告", jobData.Name), message, level))
+	}
+
+	cronjobConfig := workloadConfig.CronjobConfig
+	if cronjobConfig == nil {
+		cronjobConfig = apis.NewCronjobConfig()
+	}
+
+	for _, cj := range workloadConfig.Cronjob {
+		cronJob, err := client.Clientset.BatchV1().CronJobs(cj.Namespace).Get(context.TODO(), cj.Name, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+
+		jobs, err := getCronJobChildJobs(client.Clientset, cronJob)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pods := apis.NewPods()
+		for _, job := range jobs {
+			set := labels.Set(job.Spec.Selector.MatchLabels)
+			jobPods, err := GetPod(cj.Regexp, job.Namespace, set, client.Clientset)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			pods = append(pods, jobPods...)
+		}
+
+		var cronJobInspections []*apis.Inspection
+		if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+			cronJobInspections = append(cronJobInspections, apis.NewInspection(fmt.Sprintf("Cronjob %s 警告", cronJob.Name), fmt.Sprintf("命名空间 %s 下的 Cronjob %s 已被挂起", cronJob.Namespace, cronJob.Name), 1))
+		}
+
+		missed, err := isCronJobMissedSchedules(cronJob, cronjobConfig.MissedSchedules)
+		if err != nil {
+			cronJobInspections = append(cronJobInspections, apis.NewInspection(fmt.Sprintf("Cronjob %s 警告", cronJob.Name), fmt.Sprintf("命名空间 %s 下的 Cronjob %s 调度规则 %s 解析失败: %v", cronJob.Namespace, cronJob.Name, cronJob.Spec.Schedule, err), 2))
+		} else if missed {
+			cronJobInspections = append(cronJobInspections, apis.NewInspection(fmt.Sprintf("Cronjob %s 警告", cronJob.Name), fmt.Sprintf("命名空间 %s 下的 Cronjob %s 已超过 %d 个调度周期没有成功执行", cronJob.Namespace, cronJob.Name, cronjobConfig.MissedSchedules), 2))
+		}
+
+		if len(jobs) > 0 && isJobFailed(jobs[0]) {
+			cronJobInspections = append(cronJobInspections, apis.NewInspection(fmt.Sprintf("Cronjob %s 警告", cronJob.Name), fmt.Sprintf("命名空间 %s 下的 Cronjob %s 最近一次执行的 Job %s 失败", cronJob.Namespace, cronJob.Name, jobs[0].Name), 2))
+		}
+
+		if cronjobConfig.MaxActiveJobs > 0 && len(cronJob.Status.Active) > cronjobConfig.MaxActiveJobs {
+			cronJobInspections = append(cronJobInspections, apis.NewInspection(fmt.Sprintf("Cronjob %s 警告", cronJob.Name), fmt.Sprintf("命名空间 %s 下的 Cronjob %s 有 %d 个运行中的 Job，超过 %d 个", cronJob.Namespace, cronJob.Name, len(cronJob.Status.Active), cronjobConfig.MaxActiveJobs), 2))
+		}
+
+		cjState := success
+		if len(cronJobInspections) > 0 {
+			cjState = warning
+		}
+
+		var condition []apis.Condition
+		if len(jobs) > 0 {
+			for _, c := range jobs[0].Status.Conditions {
+				condition = append(condition, apis.Condition{
+					Type:    string(c.Type),
+					Status:  string(c.Status),
+					Reason:  c.Reason,
+					Message: c.Message,
+				})
+			}
+		}
+
+		cronJobData := &apis.WorkloadData{
+			Name:      cronJob.Name,
+			Namespace: cronJob.Namespace,
+			Pods:      pods,
+			Status: &apis.Status{
+				State:     cjState,
+				Condition: condition,
+			},
 		}
+
+		ResourceWorkloadArray.Cronjob = append(ResourceWorkloadArray.Cronjob, cronJobData)
+		resourceInspections = append(resourceInspections, cronJobInspections...)
 	}
 
 	return ResourceWorkloadArray, resourceInspections, nil
 }
 
+func GetHorizontalPodAutoscalers(client *apis.Client) ([]*apis.HorizontalPodAutoscaler, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	hpas := apis.NewHorizontalPodAutoscalers()
+
+	hpaList, err := client.Clientset.AutoscalingV2().HorizontalPodAutoscalers("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, h := range hpaList.Items {
+		target := fmt.Sprintf("%s/%s", h.Spec.ScaleTargetRef.Kind, h.Spec.ScaleTargetRef.Name)
+		minReplicas := int32(1)
+		if h.Spec.MinReplicas != nil {
+			minReplicas = *h.Spec.MinReplicas
+		}
+
+		exists, err := isScaleTargetExists(client.Clientset, h.Namespace, h.Spec.ScaleTargetRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 HPA %s 的目标工作负载不存在", h.Namespace, h.Name), fmt.Sprintf("HPA %s 的目标 %s 不存在", h.Name, target), 2))
+		}
+
+		if minReplicas < h.Spec.MaxReplicas && h.Status.CurrentReplicas >= h.Spec.MaxReplicas {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 HPA %s 已达到最大副本数", h.Namespace, h.Name), fmt.Sprintf("HPA %s 当前副本数 %d 已达到 maxReplicas %d，无法继续扩容", h.Name, h.Status.CurrentReplicas, h.Spec.MaxReplicas), 1))
+		}
+
+		var condition []apis.Condition
+		for _, c := range h.Status.Conditions {
+			condition = append(condition, apis.Condition{
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
+			})
+
+			if c.Status != corev1.ConditionFalse || (c.Type != autoscalingv2.ScalingActive && c.Type != autoscalingv2.AbleToScale) {
+				continue
+			}
+
+			if strings.HasPrefix(c.Reason, "FailedGet") {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 HPA %s 无法获取指标", h.Namespace, h.Name), fmt.Sprintf("%s: %s，请检查 metrics-server 是否正常", c.Reason, c.Message), 2))
+			} else {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 HPA %s %s 为 False", h.Namespace, h.Name, c.Type), fmt.Sprintf("%s: %s", c.Reason, c.Message), 2))
+			}
+		}
+
+		hpas = append(hpas, &apis.HorizontalPodAutoscaler{
+			Name:            h.Name,
+			Namespace:       h.Namespace,
+			Target:          target,
+			MinReplicas:     minReplicas,
+			MaxReplicas:     h.Spec.MaxReplicas,
+			CurrentReplicas: h.Status.CurrentReplicas,
+			Condition:       condition,
+		})
+	}
+
+	return hpas, resourceInspections, nil
+}
+
+func isScaleTargetExists(clientset *kubernetes.Clientset, namespace string, ref autoscalingv2.CrossVersionObjectReference) (bool, error) {
+	var err error
+	switch ref.Kind {
+	case "Deployment":
+		_, err = clientset.AppsV1().Deployments(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		_, err = clientset.AppsV1().StatefulSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		_, err = clientset.AppsV1().ReplicaSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	default:
+		return true, nil
+	}
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 func GetPod(regexpString, namespace string, set labels.Set, clientset *kubernetes.Clientset) ([]*apis.Pod, error) {
 	pods := apis.NewPods()
 
@@ -550,48 +826,174 @@ func GetServices(client *apis.Client) ([]*apis.Service, []*apis.Inspection, erro
 		return nil, nil, err
 	}
 
+	// 获取所有 EndpointSlices，并按所属 Service 分组
+	endpointSliceList, err := client.Clientset.DiscoveryV1().EndpointSlices("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpointSlices := make(map[string][]discoveryv1.EndpointSlice)
+	for _, e := range endpointSliceList.Items {
+		serviceName := e.Labels[discoveryv1.LabelServiceName]
+		if serviceName == "" {
+			continue
+		}
+		key := e.Namespace + "/" + serviceName
+		endpointSlices[key] = append(endpointSlices[key], e)
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var externalNames []string
 	for _, s := range serviceList.Items {
-		// 获取对应的 Endpoints
-		endpoints, err := client.Clientset.CoreV1().Endpoints(s.Namespace).Get(context.TODO(), s.Name, metav1.GetOptions{})
-		if err != nil {
-			if k8serrors.IsNotFound(err) {
-				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 找不到对应 endpoint", s.Namespace, s.Name), fmt.Sprintf(""), 1))
+		if s.Spec.Type == corev1.ServiceTypeExternalName && !isClusterInternalHost(s.Spec.ExternalName) {
+			externalNames = append(externalNames, s.Spec.ExternalName)
+		}
+	}
+	unresolvableHosts := getUnresolvableHosts(externalNames)
+
+	for _, s := range serviceList.Items {
+		serviceData := &apis.Service{
+			Name:      s.Name,
+			Namespace: s.Namespace,
+			Type:      string(s.Spec.Type),
+		}
+		services = append(services, serviceData)
+
+		if s.Spec.Type == corev1.ServiceTypeExternalName {
+			if unresolvableHosts[s.Spec.ExternalName] {
+				serviceData.UnresolvedExternalName = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 的 ExternalName 无法解析", s.Namespace, s.Name), fmt.Sprintf("域名 %s 无法解析", s.Spec.ExternalName), 1))
+			}
+			continue
+		}
+
+		if s.Spec.Type == corev1.ServiceTypeLoadBalancer && len(s.Status.LoadBalancer.Ingress) == 0 {
+			serviceData.PendingLoadBalancer = true
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 LoadBalancer Service %s 没有分配地址", s.Namespace, s.Name), fmt.Sprintf("Service %s 的 status.loadBalancer.ingress 为空", s.Name), 2))
+		}
+
+		var pods []*corev1.Pod
+		if len(s.Spec.Selector) > 0 {
+			pods = getSelectedPods(podList.Items, s.Namespace, labels.SelectorFromSet(s.Spec.Selector))
+			if len(pods) == 0 {
+				serviceData.NoMatchingPods = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 的 selector 没有匹配任何 Pod", s.Namespace, s.Name), fmt.Sprintf("selector %s 没有匹配任何 Pod", labels.Set(s.Spec.Selector).String()), 1))
 				continue
 			}
-			return nil, nil, err
+
+			for _, port := range s.Spec.Ports {
+				if port.TargetPort.Type == intstr.String && !isContainerPortNameExists(pods, port.TargetPort.StrVal) {
+					serviceData.MissingTargetPort = true
+					resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 的 targetPort 不存在", s.Namespace, s.Name), fmt.Sprintf("选中的 Pod 中没有名为 %s 的容器端口", port.TargetPort.StrVal), 2))
+				}
+			}
 		}
 
-		// 检查 Endpoints 是否为空
-		var emptyEndpoints bool
-		if len(endpoints.Subsets) == 0 {
-			emptyEndpoints = true
-			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 对应 Endpoints 没有 Subsets", s.Namespace, s.Name), fmt.Sprintf(""), 1))
+		// 检查 EndpointSlices 是否存在就绪的 Endpoint
+		serviceEndpointSlices, ok := endpointSlices[s.Namespace+"/"+s.Name]
+		if !ok {
+			if len(s.Spec.Selector) > 0 {
+				serviceData.EmptyEndpoints = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 找不到对应 EndpointSlice", s.Namespace, s.Name), fmt.Sprintf(""), 1))
+			}
+			continue
 		}
 
-		services = append(services, &apis.Service{
-			Name:           s.Name,
-			Namespace:      s.Namespace,
-			EmptyEndpoints: emptyEndpoints,
-		})
+		if getReadyEndpointCount(serviceEndpointSlices) == 0 {
+			serviceData.EmptyEndpoints = true
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Service %s 对应 EndpointSlice 没有就绪的 Endpoint", s.Namespace, s.Name), fmt.Sprintf(""), 1))
+		}
 	}
 
 	return services, resourceInspections, nil
 }
 
-func GetIngress(client *apis.Client) ([]*apis.Ingress, []*apis.Inspection, error) {
+func getReadyEndpointCount(endpointSlices []discoveryv1.EndpointSlice) int {
+	count := 0
+	for _, e := range endpointSlices {
+		for _, endpoint := range e.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func isContainerPortNameExists(pods []*corev1.Pod, name string) bool {
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isClusterInternalHost 集群内域名以及依赖 search 域补全的短域名只能在被巡检集群内解析
+func isClusterInternalHost(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	return !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") || strings.Contains(host, ".svc.") || strings.HasSuffix(host, ".cluster.local")
+}
+
+// getUnresolvableHosts 并发解析域名，所有解析共用 5 秒超时，返回无法解析的域名
+func getUnresolvableHosts(hosts []string) map[string]bool {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+
+	result := make(map[string]bool)
+	var uniqueHosts []string
+	for _, host := range hosts {
+		if _, ok := result[host]; !ok {
+			result[host] = false
+			uniqueHosts = append(uniqueHosts, host)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, host := range uniqueHosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+
+			addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+			if err != nil || len(addrs) == 0 {
+				mu.Lock()
+				result[host] = true
+				mu.Unlock()
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	return result
+}
+
+func GetIngress(client *apis.Client, ingressConfig *apis.IngressConfig) ([]*apis.Ingress, []*apis.Inspection, error) {
 	resourceInspections := apis.NewInspections()
 	ingress := apis.NewIngress()
 
 	// 获取所有命名空间下的所有 Ingress
 	ingresseList, err := client.Clientset.NetworkingV1().Ingresses("").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Fatalf("Error listing ingresses: %v", err)
+		return nil, nil, err
 	}
 
 	// 使用 map 来记录 host+path 与 ingress 名称的映射
 	ingressMap := make(map[string][]string)
 	for _, i := range ingresseList.Items {
 		for _, rule := range i.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
 			host := rule.Host
 			for _, path := range rule.HTTP.Paths {
 				key := host + path.Path
@@ -623,11 +1025,7 @@ func GetIngress(client *apis.Client) ([]*apis.Ingress, []*apis.Inspection, error
 			parts := strings.Split(NamespaceName, "/")
 			for index, i := range ingress {
 				if parts[0] == i.Namespace && parts[1] == i.Name {
-					ingress[index] = &apis.Ingress{
-						Name:          i.Name,
-						Namespace:     i.Namespace,
-						DuplicatePath: true,
-					}
+					ingress[index].DuplicatePath = true
 				}
 			}
 
@@ -637,9 +1035,217 @@ func GetIngress(client *apis.Client) ([]*apis.Ingress, []*apis.Inspection, error
 		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Ingress %s 存在重复的 Path", strings.Join(result, ", ")), fmt.Sprintf(""), 1))
 	}
 
+	serviceList, err := client.Clientset.CoreV1().Services("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	services := make(map[string]*corev1.Service)
+	for i := range serviceList.Items {
+		services[serviceList.Items[i].Namespace+"/"+serviceList.Items[i].Name] = &serviceList.Items[i]
+	}
+
+	ingressClassList, err := client.Clientset.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ingressClasses := make(map[string]bool)
+	for _, c := range ingressClassList.Items {
+		ingressClasses[c.Name] = true
+	}
+
+	certificateExpiryDays := ingressConfig.CertificateExpiryDays
+	if certificateExpiryDays <= 0 {
+		certificateExpiryDays = defaultCertificateExpiryDays
+	}
+
+	// 记录 host 被哪些命名空间的 Ingress 使用
+	hostNamespaces := make(map[string]map[string]bool)
+	hostIngress := make(map[string][]int)
+	for index, i := range ingresseList.Items {
+		ingressData := ingress[index]
+
+		if i.Spec.IngressClassName != nil && !ingressClasses[*i.Spec.IngressClassName] {
+			ingressData.MissingIngressClass = true
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 的 IngressClass 不存在", i.Namespace, i.Name), fmt.Sprintf("IngressClass %s 不存在", *i.Spec.IngressClassName), 2))
+		}
+
+		var backends []networkingv1.IngressBackend
+		if i.Spec.DefaultBackend != nil {
+			backends = append(backends, *i.Spec.DefaultBackend)
+		}
+		for _, rule := range i.Spec.Rules {
+			if rule.Host != "" {
+				if hostNamespaces[rule.Host] == nil {
+					hostNamespaces[rule.Host] = make(map[string]bool)
+				}
+				hostNamespaces[rule.Host][i.Namespace] = true
+				hostIngress[rule.Host] = append(hostIngress[rule.Host], index)
+			}
+
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				backends = append(backends, path.Backend)
+			}
+		}
+
+		for _, backend := range backends {
+			if backend.Service == nil {
+				continue
+			}
+
+			service, ok := services[i.Namespace+"/"+backend.Service.Name]
+			if !ok {
+				ingressData.MissingBackend = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 的后端 Service 不存在", i.Namespace, i.Name), fmt.Sprintf("Service %s 不存在", backend.Service.Name), 2))
+				continue
+			}
+
+			if !isServicePortExists(service, backend.Service.Port) {
+				ingressData.MissingBackend = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 的后端端口不存在", i.Namespace, i.Name), fmt.Sprintf("Service %s 没有端口 %s", backend.Service.Name, getServiceBackendPort(backend.Service.Port)), 2))
+			}
+		}
+
+		for _, tls := range i.Spec.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+
+			secret, err := client.Clientset.CoreV1().Secrets(i.Namespace).Get(context.TODO(), tls.SecretName, metav1.GetOptions{})
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					ingressData.MissingTLSSecret = true
+					resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 的 TLS Secret 不存在", i.Namespace, i.Name), fmt.Sprintf("Secret %s 不存在", tls.SecretName), 2))
+					continue
+				}
+				return nil, nil, err
+			}
+
+			certificates, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+			if err != nil || len(certificates) == 0 {
+				ingressData.MissingTLSSecret = true
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 的 TLS 证书无效", i.Namespace, i.Name), fmt.Sprintf("Secret %s 中没有有效的证书", tls.SecretName), 2))
+				continue
+			}
+
+			notAfter := certificates[0].NotAfter
+			if ingressData.CertificateNotAfter == "" || notAfter.Format(time.DateTime) < ingressData.CertificateNotAfter {
+				ingressData.CertificateNotAfter = notAfter.Format(time.DateTime)
+			}
+
+			if inspection := getCertificateInspection(fmt.Sprintf("命名空间 %s 下 Ingress %s 使用的 Secret %s", i.Namespace, i.Name, tls.SecretName), notAfter, certificateExpiryDays, 0); inspection != nil {
+				ingressData.ExpiringCertificate = true
+				resourceInspections = append(resourceInspections, inspection)
+			}
+		}
+	}
+
+	for host, namespaces := range hostNamespaces {
+		if len(namespaces) <= 1 {
+			continue
+		}
+
+		var result []string
+		for _, index := range hostIngress[host] {
+			ingress[index].DuplicateHost = true
+			result = append(result, fmt.Sprintf("%s/%s", ingress[index].Namespace, ingress[index].Name))
+		}
+
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("Host %s 被多个命名空间的 Ingress 使用", host), fmt.Sprintf("Ingress 列表: %s", strings.Join(result, ", ")), 1))
+	}
+
 	return ingress, resourceInspections, nil
 }
 
+func isServicePortExists(service *corev1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, p := range service.Spec.Ports {
+		if (port.Name != "" && p.Name == port.Name) || (port.Name == "" && p.Port == port.Number) {
+			return true
+		}
+	}
+	return false
+}
+
+func getServiceBackendPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%d", port.Number)
+}
+
+// getThresholdInspection 根据阈值配置判断资源占比，超过 Critical 为等级 3，超过 Warning 为等级 2
+func getThresholdInspection(nodeName, metric string, used, allocatable float64, threshold *apis.ThresholdConfig) *apis.Inspection {
+	if allocatable <= 0 {
+		return nil
+	}
+
+	if threshold == nil {
+		threshold = apis.NewThresholdConfig()
+	}
+
+	percent := used / allocatable * 100
+	message := fmt.Sprintf("Node %s %s 占可分配资源的百分之 %.2f", nodeName, metric, percent)
+	if threshold.Critical > 0 && percent > threshold.Critical {
+		return apis.NewInspection(fmt.Sprintf("Node %s %s 超过百分之 %v", nodeName, metric, threshold.Critical), message, 3)
+	}
+
+	if threshold.Warning > 0 && percent > threshold.Warning {
+		return apis.NewInspection(fmt.Sprintf("Node %s %s 超过百分之 %v", nodeName, metric, threshold.Warning), message, 2)
+	}
+
+	return nil
+}
+
+func getNodeConditionInspections(node *corev1.Node) []*apis.Inspection {
+	var nodeInspections []*apis.Inspection
+
+	for _, c := range node.Status.Conditions {
+		switch c.Type {
+		case corev1.NodeReady:
+			if c.Status != corev1.ConditionTrue {
+				nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s 处于 NotReady 状态", node.Name), fmt.Sprintf("%s: %s", c.Reason, c.Message), 3))
+			}
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if c.Status == corev1.ConditionTrue {
+				nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s 存在 %s", node.Name, c.Type), fmt.Sprintf("%s: %s", c.Reason, c.Message), 2))
+			}
+		}
+	}
+
+	if node.Spec.Unschedulable {
+		nodeInspections = append(nodeInspections, apis.NewInspection(fmt.Sprintf("Node %s 已被设置为不可调度", node.Name), fmt.Sprintf("Node %s 处于 cordon 状态，新的 Pod 不会调度到该节点", node.Name), 1))
+	}
+
+	return nodeInspections
+}
+
+// getPodsResourceList 计算 Pod 的 requests 与 limits 之和，pods 为 Pod 数量
+func getPodsResourceList(pods []*corev1.Pod) (corev1.ResourceList, corev1.ResourceList) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			for name, quantity := range c.Resources.Requests {
+				value := requests[name]
+				value.Add(quantity)
+				requests[name] = value
+			}
+			for name, quantity := range c.Resources.Limits {
+				value := limits[name]
+				value.Add(quantity)
+				limits[name] = value
+			}
+		}
+	}
+	requests[corev1.ResourcePods] = *resource.NewQuantity(int64(len(pods)), resource.DecimalSI)
+
+	return requests, limits
+}
+
 func getResourceList(val string) corev1.ResourceList {
 	if val == "" {
 		return nil
@@ -651,13 +1257,64 @@ func getResourceList(val string) corev1.ResourceList {
 	return result
 }
 
-func isDeploymentAvailable(deployment *appsv1.Deployment) bool {
-	for _, condition := range deployment.Status.Conditions {
-		if (condition.Type == "Failed" && condition.Status == "False") || condition.Reason == "Error" {
-			return false
+// getDeploymentRolloutIssues 根据 Deployment 的状态条件判断滚动更新是否健康，返回异常原因以及告警级别
+func getDeploymentRolloutIssues(deployment *appsv1.Deployment, conditions []apis.Condition) ([]string, int) {
+	var issues []string
+	level := 0
+	addIssue := func(issue string, issueLevel int) {
+		issues = append(issues, issue)
+		if issueLevel > level {
+			level = issueLevel
+		}
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	if deployment.Generation != deployment.Status.ObservedGeneration {
+		addIssue(fmt.Sprintf("控制器尚未处理最新的配置，generation %d，observedGeneration %d", deployment.Generation, deployment.Status.ObservedGeneration), 1)
+	}
+
+	var deadlineExceeded bool
+	for _, c := range conditions {
+		switch {
+		case c.Type == string(appsv1.DeploymentProgressing) && c.Reason == "ProgressDeadlineExceeded":
+			deadlineExceeded = true
+			addIssue(fmt.Sprintf("滚动更新超过 progressDeadlineSeconds 仍未完成: %s", c.Message), 2)
+		case c.Type == string(appsv1.DeploymentReplicaFailure) && c.Status == string(corev1.ConditionTrue):
+			addIssue(fmt.Sprintf("创建 Pod 失败 %s: %s", c.Reason, c.Message), 2)
+		case c.Type == string(appsv1.DeploymentAvailable) && c.Status == string(corev1.ConditionFalse):
+			addIssue(fmt.Sprintf("可用副本数低于 maxUnavailable 允许的最小值: %s", c.Message), 2)
+		}
+	}
+
+	// 新旧 ReplicaSet 的 Pod 同时存在且 Progressing 条件长时间没有更新，说明滚动更新卡住
+	if !deadlineExceeded && deployment.Status.UpdatedReplicas < deployment.Status.Replicas {
+		for _, c := range deployment.Status.Conditions {
+			if c.Type != appsv1.DeploymentProgressing || c.Reason == "NewReplicaSetAvailable" {
+				continue
+			}
+
+			stuckTime := time.Since(c.LastUpdateTime.Time)
+			if stuckTime < time.Duration(stuckRolloutMinutes)*time.Minute {
+				continue
+			}
+
+			if deployment.Spec.Paused {
+				addIssue(fmt.Sprintf("滚动更新已暂停 %s，新版本副本 %d 个，共 %d 个副本", stuckTime.Truncate(time.Minute), deployment.Status.UpdatedReplicas, deployment.Status.Replicas), 1)
+			} else {
+				addIssue(fmt.Sprintf("滚动更新 %s 没有进展，新版本副本 %d 个，共 %d 个副本", stuckTime.Truncate(time.Minute), deployment.Status.UpdatedReplicas, deployment.Status.Replicas), 1)
+			}
 		}
 	}
-	return deployment.Status.AvailableReplicas >= *deployment.Spec.Replicas
+
+	if deployment.Status.AvailableReplicas < replicas {
+		addIssue(fmt.Sprintf("可用副本 %d 个，期望 %d 个", deployment.Status.AvailableReplicas, replicas), 2)
+	}
+
+	return issues, level
 }
 
 func isDaemonSetAvailable(daemonset *appsv1.DaemonSet) bool {
@@ -669,5 +1326,138 @@ func isStatefulSetAvailable(statefulset *appsv1.StatefulSet) bool {
 }
 
 func isJobCompleted(job *batchv1.Job) bool {
-	return job.Status.Succeeded >= *job.Spec.Completions
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getJobState 返回 Job 的状态以及处于该状态的原因，运行超过 maxRunningMinutes 的 Job 视为 warning
+func getJobState(job *batchv1.Job, maxRunningMinutes int) (string, string) {
+	if isJobCompleted(job) {
+		return success, "已完成"
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type != batchv1.JobFailed || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Reason {
+		case "BackoffLimitExceeded":
+			backoffLimit := int32(6)
+			if job.Spec.BackoffLimit != nil {
+				backoffLimit = *job.Spec.BackoffLimit
+			}
+			return failed, fmt.Sprintf("失败 %d 次，达到 backoffLimit %d", job.Status.Failed, backoffLimit)
+		case "DeadlineExceeded":
+			if job.Spec.ActiveDeadlineSeconds == nil {
+				return failed, fmt.Sprintf("运行时间超过 activeDeadlineSeconds: %s", condition.Message)
+			}
+			return failed, fmt.Sprintf("运行时间超过 activeDeadlineSeconds %d 秒", *job.Spec.ActiveDeadlineSeconds)
+		default:
+			return failed, fmt.Sprintf("执行失败: %s %s", condition.Reason, condition.Message)
+		}
+	}
+
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return warning, "已被挂起"
+	}
+
+	if job.Status.StartTime == nil {
+		return running, "等待启动"
+	}
+
+	runningTime := time.Since(job.Status.StartTime.Time)
+	if job.Spec.ActiveDeadlineSeconds != nil && runningTime > time.Duration(*job.Spec.ActiveDeadlineSeconds)*time.Second {
+		return warning, fmt.Sprintf("已运行 %s，超过 activeDeadlineSeconds %d 秒仍未终止", runningTime.Truncate(time.Second), *job.Spec.ActiveDeadlineSeconds)
+	}
+
+	if maxRunningMinutes > 0 && runningTime > time.Duration(maxRunningMinutes)*time.Minute {
+		return warning, fmt.Sprintf("已运行 %s，超过 %d 分钟", runningTime.Truncate(time.Second), maxRunningMinutes)
+	}
+
+	return running, fmt.Sprintf("运行中，已失败 %d 次", job.Status.Failed)
+}
+
+// getJobFailedPodLogs 返回失败的 Pod 以及 GetPod 按正则匹配到的最后一行日志
+func getJobFailedPodLogs(failedPods []corev1.Pod, pods []*apis.Pod) string {
+	logs := make(map[string][]string)
+	for _, p := range pods {
+		logs[p.Name] = p.Log
+	}
+
+	var result []string
+	for _, p := range failedPods {
+		if log := logs[p.Name]; len(log) > 0 {
+			result = append(result, fmt.Sprintf("%s (%s)", p.Name, strings.TrimSpace(log[len(log)-1])))
+		} else {
+			result = append(result, p.Name)
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getCronJobChildJobs 返回 CronJob 创建的 Job，按创建时间从新到旧排序
+func getCronJobChildJobs(clientset *kubernetes.Clientset, cronJob *batchv1.CronJob) ([]*batchv1.Job, error) {
+	jobList, err := clientset.BatchV1().Jobs(cronJob.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*batchv1.Job
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		owner := metav1.GetControllerOf(job)
+		if owner != nil && owner.UID == cronJob.UID {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[j].CreationTimestamp.Before(&jobs[i].CreationTimestamp)
+	})
+
+	return jobs, nil
+}
+
+// isCronJobMissedSchedules 判断 CronJob 最近一次成功执行后是否已错过 missedSchedules 个调度周期
+func isCronJobMissedSchedules(cronJob *batchv1.CronJob, missedSchedules int) (bool, error) {
+	if missedSchedules <= 0 || (cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend) {
+		return false, nil
+	}
+
+	spec := cronJob.Spec.Schedule
+	if cronJob.Spec.TimeZone != nil && !strings.Contains(spec, "TZ") {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", *cronJob.Spec.TimeZone, spec)
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return false, err
+	}
+
+	last := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastSuccessfulTime != nil {
+		last = cronJob.Status.LastSuccessfulTime.Time
+	}
+
+	for i := 0; i < missedSchedules; i++ {
+		last = schedule.Next(last)
+	}
+
+	return time.Now().After(last), nil
 }
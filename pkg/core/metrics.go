@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+)
+
+var (
+	podMetricsResource  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+	// 每个节点报告的用量最高的 Pod 数量
+	topConsumerPodCount = 5
+)
+
+// getPodMetrics 通过 metrics.k8s.io 获取 Pod 中每个容器的实际用量，键为 namespace/name
+func getPodMetrics(ctx context.Context, client *apis.Client, namespace string) (map[string]map[string]corev1.ResourceList, error) {
+	podMetricsList, err := client.DynamicClient.Resource(podMetricsResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]corev1.ResourceList)
+	for _, p := range podMetricsList.Items {
+		containers, _, err := unstructured.NestedSlice(p.Object, "containers")
+		if err != nil {
+			continue
+		}
+
+		usages := make(map[string]corev1.ResourceList)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _, _ := unstructured.NestedString(container, "name")
+			usage, _, _ := unstructured.NestedStringMap(container, "usage")
+			usages[name] = parseResourceList(usage)
+		}
+		result[p.GetNamespace()+"/"+p.GetName()] = usages
+	}
+
+	return result, nil
+}
+
+func parseResourceList(usage map[string]string) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for name, value := range usage {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			result[corev1.ResourceName(name)] = quantity
+		}
+	}
+	return result
+}
+
+// getNodeMetrics 通过 metrics.k8s.io 获取节点的实际用量，键为节点名称
+func getNodeMetrics(ctx context.Context, client *apis.Client) (map[string]corev1.ResourceList, error) {
+	nodeMetricsList, err := client.DynamicClient.Resource(nodeMetricsResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]corev1.ResourceList)
+	for _, n := range nodeMetricsList.Items {
+		usage, _, _ := unstructured.NestedStringMap(n.Object, "usage")
+		result[n.GetName()] = parseResourceList(usage)
+	}
+
+	return result, nil
+}
+
+// getTopPodUsages 返回节点上 CPU 用量最高的 Pod
+func getTopPodUsages(pods []*corev1.Pod, podMetrics map[string]map[string]corev1.ResourceList, count int) []*apis.PodUsage {
+	var result []*apis.PodUsage
+	for _, pod := range pods {
+		metrics, ok := podMetrics[pod.Namespace+"/"+pod.Name]
+		if !ok {
+			continue
+		}
+
+		usage := &apis.PodUsage{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		}
+		for _, u := range metrics {
+			usage.MilliCPU += u.Cpu().MilliValue()
+			usage.Memory += u.Memory().Value()
+		}
+		result = append(result, usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MilliCPU != result[j].MilliCPU {
+			return result[i].MilliCPU > result[j].MilliCPU
+		}
+		return result[i].Memory > result[j].Memory
+	})
+
+	if len(result) > count {
+		result = result[:count]
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+)
+
+type networkPolicyChecker struct{}
+
+func (c *networkPolicyChecker) Name() string { return "network_policy" }
+
+func (c *networkPolicyChecker) Category() Category { return CategoryResource }
+
+func (c *networkPolicyChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.NetworkPolicyConfig != nil && config.ClusterResourceConfig.NetworkPolicyConfig.Enable
+}
+
+func (c *networkPolicyChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.NetworkPolicyConfig
+}
+
+func (c *networkPolicyChecker) Dependencies() []string { return []string{"namespace"} }
+
+// Run 在命名空间巡检之后执行，将覆盖情况写入命名空间数据
+func (c *networkPolicyChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	namespaces, inspections, err := GetNetworkPolicies(ctx, client, config.ClusterResourceConfig.NetworkPolicyConfig, kubernetes.ClusterResource.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Namespace = namespaces
+	return inspections, nil
+}
+
+// GetNetworkPolicies 检查命名空间的 NetworkPolicy 覆盖情况，命名空间巡检未开启时会补充对应的命名空间数据
+func GetNetworkPolicies(ctx context.Context, client *apis.Client, networkPolicyConfig *apis.NetworkPolicyConfig, namespaces []*apis.Namespace) ([]*apis.Namespace, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	namespaceList, err := client.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	networkPolicyList, err := client.Clientset.NetworkingV1().NetworkPolicies("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	networkPolicies := make(map[string][]networkingv1.NetworkPolicy)
+	for _, np := range networkPolicyList.Items {
+		networkPolicies[np.Namespace] = append(networkPolicies[np.Namespace], np)
+	}
+
+	namespaceData := make(map[string]*apis.Namespace)
+	for _, n := range namespaces {
+		namespaceData[n.Name] = n
+	}
+
+	for _, n := range namespaceList.Items {
+		var defaultDeny bool
+		for _, np := range networkPolicies[n.Name] {
+			if isDefaultDenyNetworkPolicy(&np) {
+				defaultDeny = true
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+			if err != nil || selector.Empty() {
+				continue
+			}
+			if len(getSelectedPods(podList.Items, np.Namespace, selector)) == 0 && !slices.Contains(networkPolicyConfig.ExcludeNamespaces, n.Name) {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 NetworkPolicy %s 没有匹配的 Pod", np.Namespace, np.Name), fmt.Sprintf("NetworkPolicy %s 的 podSelector %s 没有匹配任何 Pod", np.Name, selector.String()), 1))
+			}
+		}
+
+		count := len(networkPolicies[n.Name])
+		data, ok := namespaceData[n.Name]
+		if !ok {
+			data = &apis.Namespace{Name: n.Name}
+			namespaces = append(namespaces, data)
+		}
+		data.NetworkPolicyCount = count
+		data.EmptyNetworkPolicy = count == 0
+		data.DefaultDenyNetworkPolicy = defaultDeny
+
+		if slices.Contains(networkPolicyConfig.ExcludeNamespaces, n.Name) {
+			continue
+		}
+
+		if count == 0 {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 没有设置 NetworkPolicy", n.Name), fmt.Sprintf("命名空间 %s 下的 Pod 可以被任意来源访问", n.Name), 1))
+		} else if !defaultDeny {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 没有默认拒绝的 NetworkPolicy", n.Name), fmt.Sprintf("命名空间 %s 下未被 NetworkPolicy 选中的 Pod 可以被任意来源访问", n.Name), 1))
+		}
+	}
+
+	return namespaces, resourceInspections, nil
+}
+
+// isDefaultDenyNetworkPolicy 选中所有 Pod 且不允许任何入站流量的策略
+func isDefaultDenyNetworkPolicy(np *networkingv1.NetworkPolicy) bool {
+	if len(np.Spec.PodSelector.MatchLabels) != 0 || len(np.Spec.PodSelector.MatchExpressions) != 0 {
+		return false
+	}
+
+	if len(np.Spec.PolicyTypes) == 0 {
+		return len(np.Spec.Ingress) == 0
+	}
+
+	for _, t := range np.Spec.PolicyTypes {
+		if t == networkingv1.PolicyTypeIngress {
+			return len(np.Spec.Ingress) == 0
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+var (
+	defaultRestartThreshold = 5
+	defaultPendingMinutes   = 10
+)
+
+type podChecker struct{}
+
+func (c *podChecker) Name() string { return "pod" }
+
+func (c *podChecker) Category() Category { return CategoryResource }
+
+func (c *podChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.PodConfig != nil && config.ClusterResourceConfig.PodConfig.Enable
+}
+
+func (c *podChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.PodConfig
+}
+
+func (c *podChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetPodHealth(ctx, client, config.ClusterResourceConfig.PodConfig)
+}
+
+func GetPodHealth(ctx context.Context, client *apis.Client, podConfig *apis.PodConfig) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	restartThreshold := podConfig.RestartThreshold
+	if restartThreshold <= 0 {
+		restartThreshold = defaultRestartThreshold
+	}
+
+	pendingMinutes := podConfig.PendingMinutes
+	if pendingMinutes <= 0 {
+		pendingMinutes = defaultPendingMinutes
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range podList.Items {
+		var containerStatuses []corev1.ContainerStatus
+		containerStatuses = append(containerStatuses, pod.Status.InitContainerStatuses...)
+		containerStatuses = append(containerStatuses, pod.Status.ContainerStatuses...)
+		for _, cs := range containerStatuses {
+			if cs.State.Waiting != nil {
+				switch cs.State.Waiting.Reason {
+				case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull":
+					resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Pod %s 容器 %s 处于 %s 状态", pod.Namespace, pod.Name, cs.Name, cs.State.Waiting.Reason), cs.State.Waiting.Message, 2))
+				}
+			}
+
+			if (cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled") || (cs.State.Terminated != nil && cs.State.Terminated.Reason == "OOMKilled") {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Pod %s 容器 %s 因内存不足被终止", pod.Namespace, pod.Name, cs.Name), fmt.Sprintf("容器 %s 最近一次终止原因为 OOMKilled", cs.Name), 2))
+			}
+
+			if int(cs.RestartCount) > restartThreshold {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Pod %s 容器 %s 重启次数过多", pod.Namespace, pod.Name, cs.Name), fmt.Sprintf("容器 %s 已重启 %d 次，超过 %d 次", cs.Name, cs.RestartCount, restartThreshold), 1))
+			}
+		}
+
+		if pod.Status.Phase == corev1.PodPending && time.Since(pod.CreationTimestamp.Time) > time.Duration(pendingMinutes)*time.Minute {
+			message := fmt.Sprintf("Pod %s 处于 Pending 状态超过 %d 分钟", pod.Name, pendingMinutes)
+			for _, c := range pod.Status.Conditions {
+				if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+					message = fmt.Sprintf("%s: %s", c.Reason, c.Message)
+				}
+			}
+
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Pod %s 处于 Pending 状态", pod.Namespace, pod.Name), message, 2))
+		}
+	}
+
+	return resourceInspections, nil
+}
+
+// getWorkloadOwners 返回 ReplicaSet 和 Job 的控制器，key 为 namespace/kind/name
+func getWorkloadOwners(ctx context.Context, client *apis.Client, namespace string) (map[string]*metav1.OwnerReference, error) {
+	owners := make(map[string]*metav1.OwnerReference)
+
+	replicaSetList, err := client.Clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range replicaSetList.Items {
+		rs := &replicaSetList.Items[i]
+		if owner := metav1.GetControllerOf(rs); owner != nil {
+			owners[rs.Namespace+"/ReplicaSet/"+rs.Name] = owner
+		}
+	}
+
+	jobList, err := client.Clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if owner := metav1.GetControllerOf(job); owner != nil {
+			owners[job.Namespace+"/Job/"+job.Name] = owner
+		}
+	}
+
+	return owners, nil
+}
+
+// getPodWorkload 返回 Pod 所属的工作负载，ReplicaSet 和 Job 会解析为其控制器 Deployment 和 CronJob，没有控制器的 Pod 返回其自身
+func getPodWorkload(pod *corev1.Pod, workloadOwners map[string]*metav1.OwnerReference) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+
+	if owner.Kind == "ReplicaSet" || owner.Kind == "Job" {
+		if workloadOwner, ok := workloadOwners[pod.Namespace+"/"+owner.Kind+"/"+owner.Name]; ok {
+			return workloadOwner.Kind, workloadOwner.Name
+		}
+	}
+
+	return owner.Kind, owner.Name
+}
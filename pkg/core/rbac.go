@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"inspection-server/pkg/agent"
+	"inspection-server/pkg/apis"
+	"inspection-server/pkg/common"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+	"strings"
+)
+
+type rbacChecker struct{}
+
+func (c *rbacChecker) Name() string { return "rbac" }
+
+func (c *rbacChecker) Category() Category { return CategoryResource }
+
+func (c *rbacChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.RBACConfig != nil && config.ClusterResourceConfig.RBACConfig.Enable
+}
+
+func (c *rbacChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.RBACConfig
+}
+
+func (c *rbacChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetRBAC(ctx, client, config.ClusterResourceConfig.RBACConfig)
+}
+
+// binding ClusterRoleBinding 与 RoleBinding 的公共部分
+type binding struct {
+	kind      string
+	name      string
+	namespace string
+	roleRef   rbacv1.RoleRef
+	subjects  []rbacv1.Subject
+}
+
+func GetRBAC(ctx context.Context, client *apis.Client, rbacConfig *apis.RBACConfig) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	agentBinding, err := agent.GetClusterRoleBinding()
+	if err != nil {
+		logrus.Warnf("Could not read inspection-agent ClusterRoleBinding: %v\n", err)
+		agentBinding = &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: common.AgentName},
+			Subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: common.AgentName, Namespace: common.InspectionNamespace},
+			},
+		}
+	}
+
+	serviceAccountList, err := client.Clientset.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccounts := make(map[string]bool)
+	for _, sa := range serviceAccountList.Items {
+		serviceAccounts[sa.Namespace+"/"+sa.Name] = true
+	}
+
+	clusterRoleBindingList, err := client.Clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	roleBindingList, err := client.Clientset.RbacV1().RoleBindings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var bindings []*binding
+	for _, b := range clusterRoleBindingList.Items {
+		bindings = append(bindings, &binding{kind: "ClusterRoleBinding", name: b.Name, roleRef: b.RoleRef, subjects: b.Subjects})
+	}
+	for _, b := range roleBindingList.Items {
+		bindings = append(bindings, &binding{kind: "RoleBinding", name: b.Name, namespace: b.Namespace, roleRef: b.RoleRef, subjects: b.Subjects})
+	}
+
+	for _, b := range bindings {
+		bindingName := fmt.Sprintf("%s %s", b.kind, b.name)
+		if b.namespace != "" {
+			bindingName = fmt.Sprintf("命名空间 %s 下 %s %s", b.namespace, b.kind, b.name)
+		}
+
+		for _, s := range b.subjects {
+			subject := getSubjectName(s, b.namespace)
+			isAgent := b.kind == "ClusterRoleBinding" && b.name == agentBinding.Name && isSubjectIn(s, agentBinding.Subjects)
+
+			if b.roleRef.Kind == "ClusterRole" && b.roleRef.Name == "cluster-admin" && !isAgent && !slices.Contains(rbacConfig.AllowSubjects, subject) {
+				level := 2
+				if b.kind == "RoleBinding" {
+					level = 1
+				}
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("%s 将 cluster-admin 绑定给了 %s", bindingName, subject), fmt.Sprintf("%s 不在允许绑定 cluster-admin 的主体列表中", subject), level))
+			}
+
+			if (s.Kind == rbacv1.UserKind && s.Name == "system:anonymous") || (s.Kind == rbacv1.GroupKind && s.Name == "system:unauthenticated") {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("%s 绑定了匿名主体 %s", bindingName, s.Name), fmt.Sprintf("%s 将 %s %s 授权给了未认证的请求", bindingName, b.roleRef.Kind, b.roleRef.Name), 2))
+			}
+
+			if s.Kind == rbacv1.ServiceAccountKind {
+				namespace := s.Namespace
+				if namespace == "" {
+					namespace = b.namespace
+				}
+				if !serviceAccounts[namespace+"/"+s.Name] {
+					resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("%s 绑定的 ServiceAccount 不存在", bindingName), fmt.Sprintf("ServiceAccount %s/%s 不存在", namespace, s.Name), 1))
+				}
+			}
+		}
+	}
+
+	clusterRoleList, err := client.Clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range clusterRoleList.Items {
+		if r.Name == "cluster-admin" || strings.HasPrefix(r.Name, "system:") || r.AggregationRule != nil {
+			continue
+		}
+		if rule := getWildcardRule(r.Rules); rule != "" {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("ClusterRole %s 使用了通配符权限", r.Name), rule, 1))
+		}
+	}
+
+	roleList, err := client.Clientset.RbacV1().Roles("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range roleList.Items {
+		if strings.HasPrefix(r.Name, "system:") {
+			continue
+		}
+		if rule := getWildcardRule(r.Rules); rule != "" {
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 Role %s 使用了通配符权限", r.Namespace, r.Name), rule, 1))
+		}
+	}
+
+	return resourceInspections, nil
+}
+
+// getSubjectName 返回 User/name、Group/name 或 ServiceAccount/namespace/name 格式的主体名称
+func getSubjectName(s rbacv1.Subject, bindingNamespace string) string {
+	if s.Kind == rbacv1.ServiceAccountKind {
+		namespace := s.Namespace
+		if namespace == "" {
+			namespace = bindingNamespace
+		}
+		return fmt.Sprintf("%s/%s/%s", s.Kind, namespace, s.Name)
+	}
+
+	return fmt.Sprintf("%s/%s", s.Kind, s.Name)
+}
+
+func isSubjectIn(subject rbacv1.Subject, subjects []rbacv1.Subject) bool {
+	for _, s := range subjects {
+		if s.Kind == subject.Kind && s.Name == subject.Name && s.Namespace == subject.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func getWildcardRule(rules []rbacv1.PolicyRule) string {
+	var result []string
+	for _, rule := range rules {
+		if slices.Contains(rule.Verbs, "*") || slices.Contains(rule.Resources, "*") {
+			result = append(result, fmt.Sprintf("apiGroups: %v, resources: %v, verbs: %v", rule.APIGroups, rule.Resources, rule.Verbs))
+		}
+	}
+	return strings.Join(result, "; ")
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+	"strings"
+)
+
+var (
+	// requests 低于该值时不检查是否超过实际用量
+	minCPURequestForUsage    = resource.MustParse("100m")
+	minMemoryRequestForUsage = resource.MustParse("256Mi")
+)
+
+type resourcePolicyChecker struct{}
+
+func (c *resourcePolicyChecker) Name() string { return "resource_policy" }
+
+func (c *resourcePolicyChecker) Category() Category { return CategoryResource }
+
+func (c *resourcePolicyChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.ResourcePolicyConfig != nil && config.ClusterResourceConfig.ResourcePolicyConfig.Enable
+}
+
+func (c *resourcePolicyChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.ResourcePolicyConfig
+}
+
+func (c *resourcePolicyChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetResourcePolicy(ctx, client, config.ClusterResourceConfig.ResourcePolicyConfig)
+}
+
+type podTemplate struct {
+	kind      string
+	name      string
+	namespace string
+	spec      *corev1.PodSpec
+}
+
+// containerUsage 工作负载中同名容器在所有运行中 Pod 上的用量之和
+type containerUsage struct {
+	cpu    int64
+	memory int64
+	count  int64
+}
+
+// GetResourcePolicy 检查工作负载 Pod 模板中容器的 requests、limits 和探针，并与 metrics.k8s.io 中的实际用量比较
+func GetResourcePolicy(ctx context.Context, client *apis.Client, resourcePolicyConfig *apis.ResourcePolicyConfig) ([]*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+
+	templates, err := getPodTemplates(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*containerUsage)
+	if resourcePolicyConfig.RequestUsageRatio > 0 {
+		usages, err = getWorkloadContainerUsages(ctx, client)
+		if err != nil {
+			logrus.Warnf("Failed to get pod metrics, skip comparing requests with usage: %v\n", err)
+		}
+	}
+
+	for _, t := range templates {
+		if slices.Contains(resourcePolicyConfig.ExcludeNamespaces, t.namespace) {
+			continue
+		}
+
+		for _, c := range t.spec.Containers {
+			var issues []string
+			requests := c.Resources.Requests
+			if _, ok := requests[corev1.ResourceCPU]; !ok {
+				issues = append(issues, "未设置 CPU requests")
+			}
+			if _, ok := requests[corev1.ResourceMemory]; !ok {
+				issues = append(issues, "未设置内存 requests")
+			}
+			if _, ok := c.Resources.Limits[corev1.ResourceMemory]; !ok {
+				issues = append(issues, "未设置内存 limits")
+			}
+
+			// CronJob 创建的 Pod 运行完即退出，不需要探针
+			if t.kind != "CronJob" {
+				if c.ReadinessProbe == nil {
+					issues = append(issues, "未设置 readinessProbe")
+				}
+				if c.LivenessProbe == nil {
+					issues = append(issues, "未设置 livenessProbe")
+				}
+			}
+
+			if usage, ok := usages[fmt.Sprintf("%s/%s/%s/%s", t.namespace, t.kind, t.name, c.Name)]; ok && usage.count > 0 {
+				issues = append(issues, getOverRequestIssues(requests, usage, resourcePolicyConfig.RequestUsageRatio)...)
+			}
+
+			if len(issues) > 0 {
+				resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 的容器 %s 资源配置不规范", t.namespace, t.kind, t.name, c.Name), strings.Join(issues, "; "), 1))
+			}
+		}
+	}
+
+	return resourceInspections, nil
+}
+
+func getOverRequestIssues(requests corev1.ResourceList, usage *containerUsage, ratio float64) []string {
+	var issues []string
+
+	if request, ok := requests[corev1.ResourceCPU]; ok && request.Cmp(minCPURequestForUsage) >= 0 {
+		average := usage.cpu / usage.count
+		if float64(request.MilliValue()) > float64(average)*ratio {
+			issues = append(issues, fmt.Sprintf("CPU requests %s 超过近期平均用量 %dm 的 %.0f 倍", request.String(), average, ratio))
+		}
+	}
+
+	if request, ok := requests[corev1.ResourceMemory]; ok && request.Cmp(minMemoryRequestForUsage) >= 0 {
+		average := usage.memory / usage.count
+		if float64(request.Value()) > float64(average)*ratio {
+			issues = append(issues, fmt.Sprintf("内存 requests %s 超过近期平均用量 %dMi 的 %.0f 倍", request.String(), average/1024/1024, ratio))
+		}
+	}
+
+	return issues
+}
+
+// getWorkloadContainerUsages 按 namespace/kind/name/container 汇总运行中 Pod 的实际用量
+func getWorkloadContainerUsages(ctx context.Context, client *apis.Client) (map[string]*containerUsage, error) {
+	usages := make(map[string]*containerUsage)
+
+	podMetrics, err := getPodMetrics(ctx, client, "")
+	if err != nil {
+		return usages, err
+	}
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Running"})
+	if err != nil {
+		return usages, err
+	}
+
+	workloadOwners, err := getWorkloadOwners(ctx, client, "")
+	if err != nil {
+		return usages, err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		metrics, ok := podMetrics[pod.Namespace+"/"+pod.Name]
+		if !ok {
+			continue
+		}
+
+		kind, name := getPodWorkload(pod, workloadOwners)
+		for container, usage := range metrics {
+			key := fmt.Sprintf("%s/%s/%s/%s", pod.Namespace, kind, name, container)
+			if _, ok := usages[key]; !ok {
+				usages[key] = &containerUsage{}
+			}
+			usages[key].cpu += usage.Cpu().MilliValue()
+			usages[key].memory += usage.Memory().Value()
+			usages[key].count++
+		}
+	}
+
+	return usages, nil
+}
+
+func getPodTemplates(ctx context.Context, client *apis.Client) ([]*podTemplate, error) {
+	var templates []*podTemplate
+
+	deploymentList, err := client.Clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range deploymentList.Items {
+		d := &deploymentList.Items[i]
+		templates = append(templates, &podTemplate{kind: "Deployment", name: d.Name, namespace: d.Namespace, spec: &d.Spec.Template.Spec})
+	}
+
+	statefulSetList, err := client.Clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSetList.Items {
+		s := &statefulSetList.Items[i]
+		templates = append(templates, &podTemplate{kind: "StatefulSet", name: s.Name, namespace: s.Namespace, spec: &s.Spec.Template.Spec})
+	}
+
+	daemonSetList, err := client.Clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSetList.Items {
+		d := &daemonSetList.Items[i]
+		templates = append(templates, &podTemplate{kind: "DaemonSet", name: d.Name, namespace: d.Namespace, spec: &d.Spec.Template.Spec})
+	}
+
+	cronJobList, err := client.Clientset.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range cronJobList.Items {
+		c := &cronJobList.Items[i]
+		templates = append(templates, &podTemplate{kind: "CronJob", name: c.Name, namespace: c.Namespace, spec: &c.Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	"inspection-server/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
+	"strings"
+)
+
+var dangerousCapabilities = []string{"ALL", "SYS_ADMIN", "SYS_MODULE", "SYS_PTRACE", "NET_ADMIN", "DAC_READ_SEARCH"}
+
+type securityChecker struct{}
+
+func (c *securityChecker) Name() string { return "security" }
+
+func (c *securityChecker) Category() Category { return CategoryResource }
+
+func (c *securityChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.SecurityConfig != nil && config.ClusterResourceConfig.SecurityConfig.Enable
+}
+
+func (c *securityChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.SecurityConfig
+}
+
+func (c *securityChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	securities, inspections, err := GetSecurity(ctx, client, config.ClusterResourceConfig.SecurityConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.Security = securities
+	return inspections, nil
+}
+
+func GetSecurity(ctx context.Context, client *apis.Client, securityConfig *apis.SecurityConfig) ([]*apis.Security, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	securities := apis.NewSecurities()
+
+	podList, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceAccountList, err := client.Clientset.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceAccountAutomount := make(map[string]*bool)
+	for _, sa := range serviceAccountList.Items {
+		serviceAccountAutomount[sa.Namespace+"/"+sa.Name] = sa.AutomountServiceAccountToken
+	}
+
+	workloadOwners, err := getWorkloadOwners(ctx, client, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workloads := make(map[string]bool)
+	automountWorkloads := make(map[string][]string)
+	var automountNamespaces []string
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if slices.Contains(securityConfig.AllowNamespaces, pod.Namespace) {
+			continue
+		}
+
+		kind, name := getPodWorkload(pod, workloadOwners)
+		if pod.Namespace == common.InspectionNamespace && kind == "DaemonSet" && name == common.AgentName {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+		if workloads[key] {
+			continue
+		}
+		workloads[key] = true
+
+		if !slices.Contains(securityConfig.TokenNamespaces, pod.Namespace) && isServiceAccountTokenAutomounted(pod, serviceAccountAutomount) {
+			if _, ok := automountWorkloads[pod.Namespace]; !ok {
+				automountNamespaces = append(automountNamespaces, pod.Namespace)
+			}
+			automountWorkloads[pod.Namespace] = append(automountWorkloads[pod.Namespace], fmt.Sprintf("%s %s", kind, name))
+		}
+
+		issues, level := getPodSecurityIssues(pod)
+		if len(issues) == 0 {
+			continue
+		}
+
+		securities = append(securities, &apis.Security{
+			Kind:      kind,
+			Name:      name,
+			Namespace: pod.Namespace,
+			Issues:    issues,
+		})
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 %s %s 存在安全风险", pod.Namespace, kind, name), strings.Join(issues, "; "), level))
+	}
+
+	for _, namespace := range automountNamespaces {
+		resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下工作负载自动挂载了 ServiceAccount token", namespace), fmt.Sprintf("自动挂载 token 的工作负载: %s", strings.Join(automountWorkloads[namespace], ", ")), 1))
+	}
+
+	return securities, resourceInspections, nil
+}
+
+func getPodSecurityIssues(pod *corev1.Pod) ([]string, int) {
+	var issues []string
+	level := 0
+	addIssue := func(issue string, issueLevel int) {
+		issues = append(issues, issue)
+		if issueLevel > level {
+			level = issueLevel
+		}
+	}
+
+	if pod.Spec.HostNetwork {
+		addIssue("使用 hostNetwork", 2)
+	}
+	if pod.Spec.HostPID {
+		addIssue("使用 hostPID", 2)
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.HostPath != nil {
+			addIssue(fmt.Sprintf("卷 %s 挂载了宿主机路径 %s", v.Name, v.HostPath.Path), 2)
+		}
+	}
+
+	var containers []corev1.Container
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, c := range containers {
+		sc := c.SecurityContext
+		if sc != nil && sc.Privileged != nil && *sc.Privileged {
+			addIssue(fmt.Sprintf("容器 %s 为特权容器", c.Name), 2)
+		}
+
+		if isContainerRunAsRoot(pod.Spec.SecurityContext, sc) {
+			addIssue(fmt.Sprintf("容器 %s 可能以 root 用户运行", c.Name), 1)
+		}
+
+		if sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			addIssue(fmt.Sprintf("容器 %s 未设置 readOnlyRootFilesystem", c.Name), 1)
+		}
+
+		if sc != nil && sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if slices.Contains(dangerousCapabilities, string(capability)) {
+					addIssue(fmt.Sprintf("容器 %s 添加了 %s 权限", c.Name, capability), 2)
+				} else {
+					addIssue(fmt.Sprintf("容器 %s 添加了 %s 权限", c.Name, capability), 1)
+				}
+			}
+		}
+	}
+
+	return issues, level
+}
+
+func isContainerRunAsRoot(podSecurityContext *corev1.PodSecurityContext, sc *corev1.SecurityContext) bool {
+	var runAsUser *int64
+	var runAsNonRoot *bool
+	if podSecurityContext != nil {
+		runAsUser = podSecurityContext.RunAsUser
+		runAsNonRoot = podSecurityContext.RunAsNonRoot
+	}
+	if sc != nil {
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+	}
+
+	if runAsUser != nil {
+		return *runAsUser == 0
+	}
+
+	return runAsNonRoot == nil || !*runAsNonRoot
+}
+
+func isServiceAccountTokenAutomounted(pod *corev1.Pod, serviceAccountAutomount map[string]*bool) bool {
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		return *pod.Spec.AutomountServiceAccountToken
+	}
+
+	serviceAccountName := pod.Spec.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = "default"
+	}
+
+	automount := serviceAccountAutomount[pod.Namespace+"/"+serviceAccountName]
+	return automount == nil || *automount
+}
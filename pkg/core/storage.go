@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type storageChecker struct{}
+
+func (c *storageChecker) Name() string { return "storage" }
+
+func (c *storageChecker) Category() Category { return CategoryResource }
+
+func (c *storageChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterResourceConfig != nil && config.ClusterResourceConfig.PersistentVolumeClaimConfig != nil && config.ClusterResourceConfig.PersistentVolumeClaimConfig.Enable
+}
+
+func (c *storageChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterResourceConfig.PersistentVolumeClaimConfig
+}
+
+func (c *storageChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	pvcs, pvs, inspections, err := GetPersistentVolumeClaims(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	kubernetes.ClusterResource.PersistentVolumeClaim = pvcs
+	kubernetes.ClusterResource.PersistentVolume = pvs
+	return inspections, nil
+}
+
+func GetPersistentVolumeClaims(ctx context.Context, client *apis.Client) ([]*apis.PersistentVolumeClaim, []*apis.PersistentVolume, []*apis.Inspection, error) {
+	resourceInspections := apis.NewInspections()
+	pvcs := apis.NewPersistentVolumeClaims()
+	pvs := apis.NewPersistentVolumes()
+
+	storageClassList, err := client.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	storageClasses := make(map[string]bool)
+	var defaultStorageClass bool
+	for _, sc := range storageClassList.Items {
+		storageClasses[sc.Name] = true
+		if sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" || sc.Annotations["storageclass.beta.kubernetes.io/is-default-class"] == "true" {
+			defaultStorageClass = true
+		}
+	}
+
+	if !defaultStorageClass {
+		resourceInspections = append(resourceInspections, apis.NewInspection("集群没有默认 StorageClass", "未指定 StorageClass 的 PVC 将无法动态创建存储卷", 1))
+	}
+
+	pvcList, err := client.Clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, p := range pvcList.Items {
+		var storageClass string
+		var missingStorageClass bool
+		if p.Spec.StorageClassName != nil {
+			storageClass = *p.Spec.StorageClassName
+		}
+
+		if storageClass != "" && !storageClasses[storageClass] {
+			missingStorageClass = true
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PVC %s 使用的 StorageClass 不存在", p.Namespace, p.Name), fmt.Sprintf("StorageClass %s 不存在", storageClass), 2))
+		}
+
+		switch p.Status.Phase {
+		case corev1.ClaimPending:
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PVC %s 处于 Pending 状态", p.Namespace, p.Name), fmt.Sprintf("PVC %s 尚未绑定存储卷", p.Name), 2))
+		case corev1.ClaimLost:
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("命名空间 %s 下 PVC %s 处于 Lost 状态", p.Namespace, p.Name), fmt.Sprintf("PVC %s 绑定的存储卷 %s 已丢失", p.Name, p.Spec.VolumeName), 2))
+		}
+
+		pvcs = append(pvcs, &apis.PersistentVolumeClaim{
+			Name:                p.Name,
+			Namespace:           p.Namespace,
+			State:               string(p.Status.Phase),
+			StorageClass:        storageClass,
+			VolumeName:          p.Spec.VolumeName,
+			MissingStorageClass: missingStorageClass,
+		})
+	}
+
+	pvList, err := client.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, p := range pvList.Items {
+		var claim string
+		if p.Spec.ClaimRef != nil {
+			claim = fmt.Sprintf("%s/%s", p.Spec.ClaimRef.Namespace, p.Spec.ClaimRef.Name)
+		}
+
+		switch p.Status.Phase {
+		case corev1.VolumeReleased:
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("PV %s 处于 Released 状态", p.Name), fmt.Sprintf("PV %s 原绑定的 PVC %s 已删除，存储卷未被回收", p.Name, claim), 1))
+		case corev1.VolumeFailed:
+			resourceInspections = append(resourceInspections, apis.NewInspection(fmt.Sprintf("PV %s 处于 Failed 状态", p.Name), fmt.Sprintf("PV %s 回收失败: %s", p.Name, p.Status.Message), 2))
+		}
+
+		pvs = append(pvs, &apis.PersistentVolume{
+			Name:         p.Name,
+			State:        string(p.Status.Phase),
+			StorageClass: p.Spec.StorageClassName,
+			Claim:        claim,
+		})
+	}
+
+	return pvcs, pvs, resourceInspections, nil
+}
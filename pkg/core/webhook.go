@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"inspection-server/pkg/apis"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type webhookChecker struct{}
+
+func (c *webhookChecker) Name() string { return "webhook" }
+
+func (c *webhookChecker) Category() Category { return CategoryCore }
+
+func (c *webhookChecker) Enabled(config *apis.KubernetesConfig) bool {
+	return config.ClusterCoreConfig != nil && config.ClusterCoreConfig.WebhookConfig != nil && config.ClusterCoreConfig.WebhookConfig.Enable
+}
+
+func (c *webhookChecker) ConfigSchema(config *apis.KubernetesConfig) interface{} {
+	return config.ClusterCoreConfig.WebhookConfig
+}
+
+func (c *webhookChecker) Run(ctx context.Context, client *apis.Client, config *apis.KubernetesConfig, kubernetes *apis.Kubernetes) ([]*apis.Inspection, error) {
+	return GetWebhooks(ctx, client, config.ClusterCoreConfig.WebhookConfig)
+}
+
+// admissionWebhook ValidatingWebhook 与 MutatingWebhook 的公共部分
+type admissionWebhook struct {
+	kind           string
+	configuration  string
+	name           string
+	clientConfig   admissionregistrationv1.WebhookClientConfig
+	failurePolicy  *admissionregistrationv1.FailurePolicyType
+	timeoutSeconds *int32
+}
+
+// GetWebhooks 检查准入 Webhook 指向的 Service，failurePolicy 为 Fail 的 Webhook 不可用时匹配的 API 请求都会被拒绝
+func GetWebhooks(ctx context.Context, client *apis.Client, webhookConfig *apis.WebhookConfig) ([]*apis.Inspection, error) {
+	coreInspections := apis.NewInspections()
+
+	validatingWebhookList, err := client.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	mutatingWebhookList, err := client.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*admissionWebhook
+	for _, w := range validatingWebhookList.Items {
+		for _, webhook := range w.Webhooks {
+			webhooks = append(webhooks, &admissionWebhook{kind: "ValidatingWebhookConfiguration", configuration: w.Name, name: webhook.Name, clientConfig: webhook.ClientConfig, failurePolicy: webhook.FailurePolicy, timeoutSeconds: webhook.TimeoutSeconds})
+		}
+	}
+	for _, w := range mutatingWebhookList.Items {
+		for _, webhook := range w.Webhooks {
+			webhooks = append(webhooks, &admissionWebhook{kind: "MutatingWebhookConfiguration", configuration: w.Name, name: webhook.Name, clientConfig: webhook.ClientConfig, failurePolicy: webhook.FailurePolicy, timeoutSeconds: webhook.TimeoutSeconds})
+		}
+	}
+
+	for _, w := range webhooks {
+		webhookName := fmt.Sprintf("%s %s 中 Webhook %s", w.kind, w.configuration, w.name)
+		// failurePolicy 默认为 Fail
+		failClosed := w.failurePolicy == nil || *w.failurePolicy == admissionregistrationv1.Fail
+
+		if webhookConfig.MaxTimeoutSeconds > 0 && w.timeoutSeconds != nil && *w.timeoutSeconds > webhookConfig.MaxTimeoutSeconds {
+			coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("%s 超时时间过长", webhookName), fmt.Sprintf("timeoutSeconds 为 %d 秒，超过 %d 秒", *w.timeoutSeconds, webhookConfig.MaxTimeoutSeconds), 1))
+		}
+
+		service := w.clientConfig.Service
+		if service == nil {
+			continue
+		}
+
+		_, err := client.Clientset.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+
+			level := 1
+			if failClosed {
+				level = 3
+			}
+			coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("%s 指向的 Service 不存在", webhookName), fmt.Sprintf("Service %s/%s 不存在", service.Namespace, service.Name), level))
+			continue
+		}
+
+		if !failClosed {
+			continue
+		}
+
+		endpointSliceList, err := client.Clientset.DiscoveryV1().EndpointSlices(service.Namespace).List(ctx, metav1.ListOptions{LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name})
+		if err != nil {
+			return nil, err
+		}
+
+		if getReadyEndpointCount(endpointSliceList.Items) == 0 {
+			coreInspections = append(coreInspections, apis.NewInspection(fmt.Sprintf("%s 不可用", webhookName), fmt.Sprintf("Service %s/%s 没有就绪的 Endpoint，failurePolicy 为 Fail，匹配的 API 请求将被拒绝", service.Namespace, service.Name), 3))
+		}
+	}
+
+	return coreInspections, nil
+}
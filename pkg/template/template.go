@@ -39,7 +39,17 @@ func Register() error {
 			nodeNames = append(nodeNames, n.GetName())
 		}
 
-		clusterCoreConfig := apis.NewClusterCoreConfig()
+		clusterCoreConfig := &apis.ClusterCoreConfig{
+			APIServerHealthCheck:         true,
+			EtcdHealthCheck:              true,
+			SchedulerHealthCheck:         true,
+			ControllerManagerHealthCheck: true,
+			CertificateConfig:            apis.NewCertificateConfig(),
+			WebhookConfig:                apis.NewWebhookConfig(),
+			APIServiceHealthCheck:        true,
+		}
+		clusterCoreConfig.CertificateConfig.Enable = true
+		clusterCoreConfig.WebhookConfig.Enable = true
 		clusterNodeConfig := apis.NewClusterNodeConfig()
 		clusterResourceConfig := apis.NewClusterResourceConfig()
 
@@ -47,22 +57,6 @@ func Register() error {
 			{
 				Names: nodeNames,
 				Commands: []*apis.CommandConfig{
-					{
-						Description: "API Servedr Ready Check",
-						Command:     "kubectl get --raw='/readyz'",
-					},
-					{
-						Description: "API Server Live Check",
-						Command:     "kubectl get --raw='/livez'",
-					},
-					{
-						Description: "ETCD Ready Check",
-						Command:     "kubectl get --raw='/readyz/etcd'",
-					},
-					{
-						Description: "ETCD Live Check",
-						Command:     "kubectl get --raw='/livez/etcd'",
-					},
 					{
 						Description: "Kubelet Health Check",
 						Command:     "curl -sS http://localhost:10248/healthz",
@@ -84,6 +78,7 @@ func Register() error {
 						Command:     "test-error",
 					},
 				},
+				Thresholds: apis.NewNodeThresholdConfig(),
 			},
 			{
 				Names: []string{},
@@ -93,6 +88,7 @@ func Register() error {
 						Command:     "test-error",
 					},
 				},
+				Thresholds: apis.NewNodeThresholdConfig(),
 			},
 		}
 
@@ -147,15 +143,98 @@ func Register() error {
 						Namespace: "kube-system",
 					},
 				},
+				JobConfig:     apis.NewJobConfig(),
+				CronjobConfig: apis.NewCronjobConfig(),
 			},
 			NamespaceConfig: &apis.NamespaceConfig{
 				Enable: true,
 			},
+			PersistentVolumeClaimConfig: &apis.PersistentVolumeClaimConfig{
+				Enable: true,
+			},
+			PodConfig: &apis.PodConfig{
+				Enable:           true,
+				RestartThreshold: 5,
+				PendingMinutes:   10,
+			},
+			EventConfig: &apis.EventConfig{
+				Enable:            true,
+				LookbackHours:     24,
+				IncludeNamespaces: []string{},
+				ExcludeNamespaces: []string{},
+			},
+			SecurityConfig: &apis.SecurityConfig{
+				Enable: true,
+				AllowNamespaces: []string{
+					"kube-system",
+					"kube-public",
+					"kube-node-lease",
+					"cattle-system",
+					"cattle-fleet-system",
+					"cattle-impersonation-system",
+					"ingress-nginx",
+				},
+				TokenNamespaces: []string{},
+			},
+			ImageConfig: &apis.ImageConfig{
+				Enable:          true,
+				AllowRegistries: []string{},
+			},
+			HorizontalPodAutoscalerConfig: &apis.HorizontalPodAutoscalerConfig{
+				Enable: true,
+			},
+			PodDisruptionBudgetConfig: &apis.PodDisruptionBudgetConfig{
+				Enable: true,
+			},
+			RBACConfig: &apis.RBACConfig{
+				Enable: true,
+				AllowSubjects: []string{
+					"Group/system:masters",
+				},
+			},
+			NetworkPolicyConfig: &apis.NetworkPolicyConfig{
+				Enable: true,
+				ExcludeNamespaces: []string{
+					"kube-system",
+					"kube-public",
+					"kube-node-lease",
+				},
+			},
 			ServiceConfig: &apis.ServiceConfig{
 				Enable: true,
 			},
 			IngressConfig: &apis.IngressConfig{
+				Enable:                true,
+				CertificateExpiryDays: 30,
+			},
+			FinalizerConfig: &apis.FinalizerConfig{
+				Enable:           true,
+				ThresholdMinutes: 30,
+			},
+			CleanupConfig: &apis.CleanupConfig{
+				Enable:               true,
+				RevisionHistoryLimit: 10,
+				EmptyServiceDays:     7,
+				ExcludeNamespaces: []string{
+					"kube-system",
+					"kube-public",
+					"kube-node-lease",
+				},
+			},
+			HelmConfig: &apis.HelmConfig{
+				Enable:     true,
+				MaxHistory: 10,
+			},
+			ResourcePolicyConfig: &apis.ResourcePolicyConfig{
 				Enable: true,
+				ExcludeNamespaces: []string{
+					"kube-system",
+					"kube-public",
+					"kube-node-lease",
+					"cattle-system",
+					"cattle-fleet-system",
+				},
+				RequestUsageRatio: 4,
 			},
 		}
 